@@ -0,0 +1,277 @@
+package main
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/paginator"
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/muesli/termenv"
+)
+
+// --- Accessibility ---
+//
+// Three display modes help where the default rendering falls short:
+//
+//   - ascii replaces emoji, arrows and box drawing with plain text, so
+//     columns line up whatever the font, and statuses read as words
+//   - high_contrast swaps in the High Contrast theme and drops faint text
+//   - screen_reader lays the main menu out as one sentence per app, without
+//     the logo, sparklines or charts, and implies ascii
+//
+// Each can be set under display: in config.yaml or turned on with a flag.
+// Left unset, ascii is turned on for terminals that cannot show it (TERM
+// dumb, linux or vt*, or no color support) and high_contrast for terminals
+// limited to 16 colors, where the themes' hex colors lose their contrast.
+//
+// Views build their own labels, hints and markers with glyphs, which picks
+// the ASCII stand-ins before any text is measured or padded, so columns
+// line up in either mode. User data such as app names, paths and process
+// names never passes through it. The bubbles components that draw their
+// own symbols are set up to match.
+
+// DisplayConfig chooses the display modes. Unset modes are detected from
+// the terminal.
+type DisplayConfig struct {
+	ASCII        *bool `yaml:"ascii,omitempty"`
+	HighContrast *bool `yaml:"high_contrast,omitempty"`
+	ScreenReader *bool `yaml:"screen_reader,omitempty"`
+}
+
+// displayMode is the rendering in effect
+type displayMode struct {
+	ascii        bool
+	highContrast bool
+	screenReader bool
+
+	userTheme ThemeConfig // theme.yaml, while High Contrast replaces it
+}
+
+// display is resolved whenever the config is loaded
+var display displayMode
+
+// displayFlags holds the modes turned on from the command line
+var displayFlags displayMode
+
+var highContrastTheme = ThemeConfig{Name: "High Contrast", Base: "#000000", Surface: "#000000", Text: "#ffffff", Highlight: "#00ffff", Select: "#ffff00", Kill: "#ff5f5f", Restore: "#00ff00", Suspend: "#ff87ff", Warn: "#ffaf00"}
+
+// extractDisplayFlags removes --ascii, --high-contrast and --screen-reader
+// from args and returns the modes they turn on
+func extractDisplayFlags(args []string) ([]string, displayMode) {
+	var rest []string
+	var flags displayMode
+	for _, arg := range args {
+		switch arg {
+		case "--ascii":
+			flags.ascii = true
+		case "--high-contrast":
+			flags.highContrast = true
+		case "--screen-reader":
+			flags.screenReader = true
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return rest, flags
+}
+
+// resolveDisplay works out the display modes from the command line, the
+// config and the terminal, in that order
+func resolveDisplay(cfg DisplayConfig) displayMode {
+	pick := func(flag bool, set *bool, detected bool) bool {
+		if flag {
+			return true
+		}
+		if set != nil {
+			return *set
+		}
+		return detected
+	}
+
+	term := strings.ToLower(os.Getenv("TERM"))
+	// The terminal's own capabilities; NO_COLOR asks for no color, not ASCII
+	profile := termenv.NewOutput(os.Stdout).ColorProfile()
+	plainTerm := term == "dumb" || term == "linux" || strings.HasPrefix(term, "vt")
+
+	d := displayMode{
+		ascii:        pick(displayFlags.ascii, cfg.ASCII, plainTerm || profile == termenv.Ascii),
+		highContrast: pick(displayFlags.highContrast, cfg.HighContrast, profile == termenv.ANSI),
+		screenReader: pick(displayFlags.screenReader, cfg.ScreenReader, false),
+	}
+	if d.screenReader {
+		d.ascii = true
+	}
+	return d
+}
+
+// applyDisplay resolves the display modes for a freshly loaded config and,
+// in high contrast mode, puts the High Contrast theme in place of its own
+func applyDisplay(cfg *Config) {
+	display = resolveDisplay(cfg.Display)
+	if display.highContrast {
+		display.userTheme = cfg.Theme
+		cfg.Theme = highContrastTheme
+	}
+}
+
+// savedTheme returns the theme to write to theme.yaml, which keeps the
+// user's own while High Contrast is only standing in for it
+func savedTheme(theme ThemeConfig) ThemeConfig {
+	if display.highContrast && theme == highContrastTheme && display.userTheme != (ThemeConfig{}) {
+		return display.userTheme
+	}
+	return theme
+}
+
+// ellipsis marks text cut short by truncate
+func ellipsis() string {
+	if display.ascii {
+		return "..."
+	}
+	return "…"
+}
+
+// plainList numbers a list's pages in ASCII mode, where its page dots
+// would differ only by color, and swaps its separators for ASCII
+func plainList(l *list.Model) {
+	if display.ascii {
+		l.Paginator.Type = paginator.Arabic
+		l.Styles.DividerDot = l.Styles.DividerDot.SetString(glyphs(" • "))
+		plainHelp(&l.Help)
+	}
+}
+
+// plainHelp swaps a help view's separators for ASCII in ASCII mode
+func plainHelp(h *help.Model) {
+	if display.ascii {
+		h.ShortSeparator = glyphs(" • ")
+		h.Ellipsis = ellipsis()
+	}
+}
+
+// progressOptions draws a progress bar with ASCII fill in ASCII mode
+func progressOptions(opts ...progress.Option) []progress.Option {
+	if display.ascii {
+		opts = append(opts, progress.WithFillCharacters('#', '-'))
+	}
+	return opts
+}
+
+// glyphs returns text written by SceneShift itself, such as a label, hint
+// or format string, in the current display mode. It must not be given
+// user data, which is shown as is.
+func glyphs(s string) string {
+	if display.screenReader {
+		s = screenReaderText.Replace(s)
+	}
+	if display.ascii {
+		s = asciiText.Replace(s)
+	}
+	return s
+}
+
+// screenReaderText reads separators as pauses rather than symbols
+var screenReaderText = strings.NewReplacer(
+	" • ", ", ",
+	"  │  ", ", ",
+)
+
+// asciiText replaces everything outside ASCII that the views draw. Some
+// stand-ins are wider than the glyph they replace; glyphs applies them
+// before the text is laid out, so the wider text is measured.
+var asciiText = strings.NewReplacer(
+	// Whole labels, before their parts
+	"[🔒 POLICY]", "[POLICY]",
+	"[🛡️ PROTECTED]", "[PROTECTED]",
+	"↑ more", "more above",
+	"↓ more", "more below",
+	"←/→", "Left/Right",
+	" • ", " | ",
+
+	// Status, spelled out
+	"❌", "ERROR:",
+	"✗", "ERROR:",
+	"✅", "OK:",
+	"⚠️", "WARNING:",
+	"⚠", "WARNING:",
+	"🔒", "[locked]",
+	"🛡️", "[protected]",
+	"▶️", ">",
+	"⏸️", "||",
+	"✓", "+",
+
+	// Decoration, dropped along with its space
+	"🚀 ", "", "✨ ", "", "📂 ", "", "🔧 ", "", "📥 ", "", "💾 ", "",
+	"📜 ", "", "🩺 ", "", "📦 ", "", "🔍 ", "", "➕ ", "", "📁 ", "",
+	"🔄 ", "", "🗑️ ", "",
+
+	// Arrows and markers
+	"→", "->",
+	"←", "<-",
+	"↑", "Up",
+	"↓", "Down",
+	"↻", "refresh",
+	"▲", "^",
+	"▼", "v",
+	"▸", "+", // Collapsed, beside a "> " cursor
+	"▾", "-",
+	"▶", ">",
+	"◀", "<",
+	"•", "*",
+	"…", "...",
+	"–", "-",
+
+	// Lines, bars and sparklines
+	"─", "-", "│", "|", "├", "|", "└", "`", "┤", "|",
+	"╭", "+", "╮", "+", "╰", "+", "╯", "+",
+	"▁", ".", "▂", "_", "▃", "-", "▄", "=", "▅", "+", "▆", "*", "▇", "%", "█", "#",
+	"░", "-",
+	"\uFE0F", "", // Emoji presentation selector left over
+)
+
+// sgrPattern matches an SGR escape sequence, which sets text style
+var sgrPattern = regexp.MustCompile("\x1b\\[([0-9;]*)m")
+
+// unfaint removes the faint attribute from styled text
+func unfaint(s string) string {
+	return sgrPattern.ReplaceAllStringFunc(s, func(seq string) string {
+		params := strings.Split(sgrPattern.FindStringSubmatch(seq)[1], ";")
+		var kept []string
+		for i := 0; i < len(params); i++ {
+			switch params[i] {
+			case "38", "48":
+				// A color: 5;n or 2;r;g;b follows, where 2 is not faint
+				n := 2
+				if i+1 < len(params) && params[i+1] == "2" {
+					n = 4
+				}
+				end := i + 1 + n
+				if end > len(params) {
+					end = len(params)
+				}
+				kept = append(kept, params[i:end]...)
+				i = end - 1
+			case "2":
+			default:
+				kept = append(kept, params[i])
+			}
+		}
+		if len(kept) == 0 {
+			// Faint alone; an empty sequence would reset instead
+			return ""
+		}
+		return "\x1b[" + strings.Join(kept, ";") + "m"
+	})
+}
+
+// render applies the display modes to a finished screen. Only styling is
+// changed here; text is chosen by glyphs as the views are built.
+func (d displayMode) render(s string) string {
+	if d.highContrast {
+		s = unfaint(s)
+	}
+	return s
+}
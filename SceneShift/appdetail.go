@@ -0,0 +1,345 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// --- App Detail View ---
+//
+// Enter on the main menu opens the app under the cursor with its recent CPU
+// and RAM history charted and every process it matches listed. The app is
+// tracked by name and the selected process by PID, so the view keeps
+// working if the table is re-sorted or the config reloads underneath it.
+//
+// Kill, suspend and resume act on the selected process alone after a
+// confirmation. They go through the same policy and protection checks as
+// the main actions and are written to the audit log and session history.
+
+const (
+	detailChartHeight   = 4
+	detailChartWidth    = 60
+	detailChartMinWidth = 10 // Narrower terminals show the figures alone
+)
+
+// startAppDetail opens the detail view for the app under the cursor
+func (m model) startAppDetail() (tea.Model, tea.Cmd) {
+	if !m.cursorVisible() {
+		return m, nil
+	}
+	m.detailApp = m.config.Apps[m.cursor].Name
+	m.detailPID = 0
+	m.detailConfirm = ""
+	m.detailMessage = ""
+	m.currentState = stateAppDetail
+	// Process details are only collected for this view, so fetch them now
+	return m, m.restartStats()
+}
+
+// detailEntry returns the app shown in the detail view
+func (m model) detailEntry() (AppEntry, bool) {
+	for _, app := range m.config.Apps {
+		if app.Name == m.detailApp {
+			return app, true
+		}
+	}
+	return AppEntry{}, false
+}
+
+// detailProcs returns the processes of the app in the detail view and the
+// position of the selected one, -1 if there are none
+func (m model) detailProcs() ([]procInfo, int) {
+	procs := m.appStats[m.detailApp].Procs
+	if len(procs) == 0 {
+		return nil, -1
+	}
+	for i, p := range procs {
+		if p.PID == m.detailPID {
+			return procs, i
+		}
+	}
+	return procs, 0
+}
+
+func (m model) updateAppDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	procs, pos := m.detailProcs()
+
+	if m.detailConfirm != "" {
+		switch msg.String() {
+		case "enter", "y":
+			mode := m.detailConfirm
+			m.detailConfirm = ""
+			if pos >= 0 {
+				if app := m.findAppByName(m.detailApp); app != nil {
+					m.detailMessage = m.processAction(mode, procs[pos], app)
+				} else {
+					m.detailMessage = fmt.Sprintf("%s is no longer configured", m.detailApp)
+				}
+			}
+			return m, m.restartStats()
+		case "esc", "n":
+			m.detailConfirm = ""
+			m.detailMessage = ""
+		}
+		return m, nil
+	}
+
+	switch {
+	case msg.String() == "esc", key.Matches(msg, m.keys.Details), key.Matches(msg, m.keys.Quit):
+		m.currentState = stateMenu
+	case key.Matches(msg, m.keys.Up):
+		if pos > 0 {
+			m.detailPID = procs[pos-1].PID
+		}
+	case key.Matches(msg, m.keys.Down):
+		if pos >= 0 && pos < len(procs)-1 {
+			m.detailPID = procs[pos+1].PID
+		}
+	case key.Matches(msg, m.keys.Kill):
+		m.confirmPIDAction("kill", procs, pos)
+	case key.Matches(msg, m.keys.Suspend):
+		m.confirmPIDAction("suspend", procs, pos)
+	case key.Matches(msg, m.keys.Resume):
+		m.confirmPIDAction("resume", procs, pos)
+	}
+	return m, nil
+}
+
+func (m *model) confirmPIDAction(mode string, procs []procInfo, pos int) {
+	if pos < 0 {
+		return
+	}
+	m.detailPID = procs[pos].PID
+	m.detailConfirm = mode
+	m.detailMessage = ""
+}
+
+// processAction kills, suspends or resumes a single process and returns the
+// outcome to show. app is the configured app the process belongs to, if any;
+// suspended processes are tracked on it so resume and undo find them.
+func (m *model) processAction(mode string, proc procInfo, app *AppEntry) (outcome string) {
+	// The first audit log failure is shown with the outcome as well as on
+	// the main menu
+	if m.auditErr == nil {
+		defer func() {
+			if m.auditErr != nil {
+				outcome += glyphs(" • ❌ Audit log could not be written: ") + m.auditErr.Error()
+			}
+		}()
+	}
+	pid := proc.PID
+	name, processName, execPath := proc.Name, proc.Name, proc.Exe
+	rawName, args := proc.Name, []string(nil)
+	tracked := m.explorer.suspended
+	if app != nil {
+		resolved := m.config.resolveApp(*app)
+		name, processName, execPath, args = app.Name, resolved.ProcessName, app.ExecPath, app.Args
+		rawName = app.ProcessName
+		if app.PIDs == nil {
+			app.PIDs = make(map[int32]bool)
+		}
+		tracked = app.PIDs
+	}
+	record := AuditRecord{Action: mode, App: name, Process: processName, Trigger: "manual",
+		Targets: auditTargetsByPID([]int32{pid})}
+
+	if err := m.config.Policy.CheckAction(mode, 1); err != nil {
+		record.Result, record.Error = "denied", err.Error()
+		m.logAudit(record)
+		return glyphs("🔒 ") + err.Error()
+	}
+	if m.config.isProtected(processName) || m.config.isProtected(proc.Name) {
+		record.Result, record.Error = "blocked", "protected process"
+		m.logAudit(record)
+		return fmt.Sprintf(glyphs("🛡️ %s is protected and cannot be modified"), name)
+	}
+
+	// The PID may have exited and been reused since the last refresh
+	err := checkPIDBelongs(pid, processName)
+	if err == nil {
+		switch mode {
+		case "kill":
+			var p *process.Process
+			if p, err = process.NewProcess(pid); err == nil {
+				err = p.Kill()
+			}
+			if err == nil {
+				delete(tracked, pid)
+			}
+		case "suspend":
+			if err = suspendProcess(pid); err == nil {
+				tracked[pid] = true
+			}
+		case "resume":
+			if err = resumeProcess(pid); err == nil {
+				delete(tracked, pid)
+			}
+		}
+	}
+	record.Result, record.Error = auditResult(err)
+	m.logAudit(record)
+	if err != nil {
+		return fmt.Sprintf("[ERR]  PID %d: %v", pid, err)
+	}
+
+	ops := map[string]OperationType{"kill": OpKill, "suspend": OpSuspend, "resume": OpResume}
+	m.history.Add(HistoryEntry{
+		Timestamp: time.Now(),
+		Operation: ops[mode],
+		Apps: []AppHistoryItem{{
+			Name:        name,
+			ProcessName: rawName,
+			ExecPath:    execPath,
+			Args:        args,
+			PIDs:        []int32{pid},
+		}},
+		Success: 1,
+	})
+	verbs := map[string]string{"kill": "Terminated", "suspend": "Suspended", "resume": "Resumed"}
+	return fmt.Sprintf("%s PID %d of %s", verbs[mode], pid, name)
+}
+
+// checkPIDBelongs verifies that pid is still one of the named processes
+func checkPIDBelongs(pid int32, rawNames string) error {
+	p, err := process.NewProcess(pid)
+	if err != nil {
+		return fmt.Errorf("process no longer exists")
+	}
+	name, err := p.Name()
+	if err != nil {
+		return err
+	}
+	for _, t := range strings.Split(rawNames, ",") {
+		if strings.EqualFold(name, strings.TrimSpace(t)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("now belongs to %s", name)
+}
+
+func (m model) viewAppDetail() string {
+	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Select)).Bold(true).Underline(true)
+	label := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Highlight)).Bold(true)
+	base := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Text))
+	selected := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Select)).Bold(true)
+	unselected := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Text)).Faint(true)
+	warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Warn)).Bold(true)
+	faint := lipgloss.NewStyle().Faint(true)
+	cpuStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Kill))
+	ramStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Restore))
+
+	app, ok := m.detailEntry()
+	if !ok {
+		return titleStyle.Render("APP DETAILS") + "\n\n" +
+			base.Render(fmt.Sprintf("%q is no longer configured.", m.detailApp)) + "\n\n" +
+			faint.Render("esc: back") + "\n"
+	}
+
+	var s string
+	s += titleStyle.Render("APP DETAILS: "+strings.ToUpper(app.Name)) + "\n\n"
+
+	resolved := m.config.resolveApp(app)
+	stats, hasStats := m.appStats[app.Name]
+	status := ellipsis()
+	if hasStats {
+		status = strings.ReplaceAll(stats.Status, "_", " ")
+	}
+	field := func(name, value string) {
+		if value == "" {
+			value = "-"
+		}
+		s += label.Render(fmt.Sprintf("%-10s", name)) + base.Render(value) + "\n"
+	}
+	field("Process", resolved.ProcessName)
+	field("Path", resolved.ExecPath)
+	if len(resolved.Args) > 0 {
+		field("Args", joinArgs(resolved.Args))
+	}
+	field("Status", status)
+	if stats.Processes > 0 {
+		field("Now", fmt.Sprintf("CPU %.1f%%  RAM %s  (%d processes)", stats.CPUPercent, formatMB(stats.RAMMB), stats.Processes))
+	}
+
+	series := m.statsHistory[app.Name]
+	s += "\n"
+	if len(series) < 2 {
+		s += faint.Render("Collecting history...") + "\n"
+	} else {
+		width := detailChartWidth
+		if m.width > 0 && m.width-20 < width {
+			width = m.width - 20
+		}
+		charts := !display.screenReader && width >= detailChartMinWidth
+		span := series[len(series)-1].at.Sub(series[0].at).Round(time.Second)
+		cpu, ram := seriesCPU(series), seriesRAM(series)
+
+		s += label.Render("CPU") + faint.Render(fmt.Sprintf(glyphs("  last %s • avg %.1f%% • peak %.1f%%"), span, average(cpu), peak(cpu))) + "\n"
+		if charts {
+			s += cpuStyle.Render(barChart(cpu, width, detailChartHeight, 1, func(v float64) string {
+				return fmt.Sprintf("%.1f%%", v)
+			})) + "\n"
+		}
+
+		s += label.Render("RAM") + faint.Render(fmt.Sprintf(glyphs("  last %s • avg %s • peak %s"), span, formatMB(uint64(average(ram))), formatMB(uint64(peak(ram))))) + "\n"
+		if charts {
+			s += ramStyle.Render(barChart(ram, width, detailChartHeight, 1, func(v float64) string {
+				return formatMB(uint64(v))
+			})) + "\n"
+			s += faint.Render(fmt.Sprintf("One column per refresh (%s), newest on the right", m.config.refreshInterval())) + "\n"
+		}
+	}
+
+	// Processes
+	s += "\n" + label.Render("PROCESSES") + "\n"
+	procs, pos := m.detailProcs()
+	if len(procs) == 0 {
+		s += faint.Render("  No running processes") + "\n"
+	} else {
+		s += label.Render(fmt.Sprintf("  %-8s %-8s %-10s %7s %9s  %-16s %s", "PID", "Parent", "State", "CPU", "RSS", "Started", "User")) + "\n"
+		for i, p := range procs {
+			cursor := "  "
+			if i == pos {
+				cursor = "> "
+			}
+			started := "-"
+			if !p.Started.IsZero() {
+				started = p.Started.Format("2006-01-02 15:04")
+			}
+			line := fmt.Sprintf("%s%-8d %-8d %-10s %6.1f%% %9s  %-16s %s",
+				cursor, p.PID, p.PPID, p.State, p.CPUPercent, formatMB(p.RSSMB), started, orDash(p.User))
+			if i == pos {
+				s += selected.Render(line) + "\n"
+			} else {
+				s += unselected.Render(line) + "\n"
+			}
+		}
+		cur := procs[pos]
+		s += "\n" + label.Render(fmt.Sprintf("%-10s", "Exe")) + base.Render(orDash(cur.Exe)) + "\n"
+		s += label.Render(fmt.Sprintf("%-10s", "Command")) + base.Render(orDash(truncate(cur.Cmdline, 200))) + "\n"
+	}
+
+	s += "\n"
+	switch {
+	case m.detailConfirm != "" && pos >= 0:
+		s += warnStyle.Render(fmt.Sprintf(glyphs("%s PID %d (%s)? Enter: confirm • Esc: cancel"),
+			strings.ToUpper(m.detailConfirm), procs[pos].PID, app.Name)) + "\n"
+	case m.detailMessage != "":
+		s += base.Render(m.detailMessage) + "\n"
+	}
+	s += faint.Render(fmt.Sprintf(glyphs("%s/%s: select process • %s: kill • %s: suspend • %s: resume • esc: back"),
+		hint(m.keys.Up), hint(m.keys.Down), hint(m.keys.Kill), hint(m.keys.Suspend), hint(m.keys.Resume))) + "\n"
+	return s
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
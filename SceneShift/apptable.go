@@ -0,0 +1,574 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- App Table ---
+//
+// The main menu lists apps as a table that can be sorted by any column and
+// narrowed with an incremental filter. m.cursor stays an index into
+// m.config.Apps, so selecting, editing and deleting work on the same app
+// whatever the view order; only Up/Down walk the visible rows. The table
+// scrolls to keep the cursor within the terminal height.
+
+type appColumn int
+
+const (
+	colNone appColumn = iota // Config order
+	colName
+	colStatus
+	colSafety
+	colCPU
+	colRAM
+	colTrend
+	colPIDs
+	colCategory
+	appColumnCount
+)
+
+var appColumnTitles = [...]string{"", "Name", "Status", "Safety", "CPU", "RAM", "Trend", "PIDs", "Category"}
+
+// appColumnWidths are the fixed widths; Name takes the remaining space
+var appColumnWidths = [...]int{0, 0, 10, 9, 7, 9, trendWidth, 12, 14}
+
+// trendWidth is the number of refreshes shown by the CPU sparkline
+const trendWidth = 12
+
+// trendScale is the CPU percentage a full sparkline block stands for at
+// least, so an idle app's noise stays flat instead of filling the column
+const trendScale = 5.0
+
+const minNameWidth = 12
+
+// appRow is one app as shown in the table
+type appRow struct {
+	index    int // Into m.config.Apps
+	app      AppEntry
+	stats    appStats
+	hasStats bool
+	history  []statsSample
+	safety   string
+	category string
+}
+
+// appRows returns the apps that match the filter, in display order
+func (m model) appRows() []appRow {
+	categories := map[string]string{}
+	for _, e := range m.config.safeToKillEntries() {
+		name := strings.ToLower(e.Process)
+		if _, ok := categories[name]; !ok {
+			categories[name] = strings.ReplaceAll(e.Category, "_", " ")
+		}
+	}
+
+	filter := strings.ToLower(strings.TrimSpace(m.filterInput.Value()))
+	var rows []appRow
+	for i, app := range m.config.Apps {
+		row := appRow{index: i, app: app, safety: app.SafetyLevel}
+		row.stats, row.hasStats = m.appStats[app.Name]
+		row.history = m.statsHistory[app.Name]
+		processName := m.config.expand(app.ProcessName)
+		if m.config.Policy.Locks(processName) {
+			row.safety = "locked"
+		}
+		for _, name := range strings.Split(processName, ",") {
+			if c, ok := categories[strings.ToLower(strings.TrimSpace(name))]; ok {
+				row.category = c
+				break
+			}
+		}
+		if filter != "" && !strings.Contains(strings.ToLower(app.Name), filter) &&
+			!strings.Contains(strings.ToLower(app.ProcessName), filter) &&
+			!strings.Contains(row.category, filter) {
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	if m.sortColumn != colNone {
+		sort.SliceStable(rows, func(i, j int) bool {
+			c := compareRows(rows[i], rows[j], m.sortColumn)
+			if m.sortDesc {
+				return c > 0
+			}
+			return c < 0
+		})
+	}
+	return rows
+}
+
+var statusOrder = map[string]int{"running": 0, "suspended": 1, "not_found": 2}
+var safetyOrder = map[string]int{"locked": 0, "protected": 1, "caution": 2, "safe": 3}
+
+// compareRows orders two rows by a column, returning -1, 0 or 1
+func compareRows(a, b appRow, col appColumn) int {
+	rank := func(order map[string]int, v string) int {
+		if r, ok := order[v]; ok {
+			return r
+		}
+		return len(order)
+	}
+	switch col {
+	case colName:
+		return strings.Compare(strings.ToLower(a.app.Name), strings.ToLower(b.app.Name))
+	case colStatus:
+		return sign(rank(statusOrder, a.stats.Status) - rank(statusOrder, b.stats.Status))
+	case colSafety:
+		return sign(rank(safetyOrder, a.safety) - rank(safetyOrder, b.safety))
+	case colCPU:
+		switch {
+		case a.stats.CPUPercent < b.stats.CPUPercent:
+			return -1
+		case a.stats.CPUPercent > b.stats.CPUPercent:
+			return 1
+		}
+		return 0
+	case colRAM:
+		switch {
+		case a.stats.RAMMB < b.stats.RAMMB:
+			return -1
+		case a.stats.RAMMB > b.stats.RAMMB:
+			return 1
+		}
+		return 0
+	case colTrend:
+		// By the highest CPU use within the history window
+		pa, pb := peak(seriesCPU(a.history)), peak(seriesCPU(b.history))
+		switch {
+		case pa < pb:
+			return -1
+		case pa > pb:
+			return 1
+		}
+		return 0
+	case colPIDs:
+		return sign(len(a.stats.PIDs) - len(b.stats.PIDs))
+	case colCategory:
+		// Apps without a category go last
+		switch {
+		case a.category == b.category:
+			return 0
+		case a.category == "":
+			return 1
+		case b.category == "":
+			return -1
+		}
+		return strings.Compare(a.category, b.category)
+	}
+	return 0
+}
+
+// cursorRow returns the position of the cursor in rows, or -1 if its app
+// is filtered out
+func (m model) cursorRow(rows []appRow) int {
+	for i, r := range rows {
+		if r.index == m.cursor {
+			return i
+		}
+	}
+	return -1
+}
+
+// cursorVisible reports whether the app under the cursor is shown
+func (m model) cursorVisible() bool {
+	return len(m.config.Apps) > 0 && m.cursorRow(m.appRows()) >= 0
+}
+
+// moveCursor moves the cursor by delta visible rows, snapping to the first
+// row if its app is filtered out
+func (m *model) moveCursor(delta int) {
+	rows := m.appRows()
+	if len(rows) == 0 {
+		return
+	}
+	pos := m.cursorRow(rows)
+	if pos < 0 {
+		m.cursor = rows[0].index
+		return
+	}
+	pos += delta
+	if pos < 0 {
+		pos = 0
+	}
+	if pos >= len(rows) {
+		pos = len(rows) - 1
+	}
+	m.cursor = rows[pos].index
+}
+
+// deleteCursorApp removes the app under the cursor, moving the cursor to
+// the next visible row
+func (m *model) deleteCursorApp() {
+	rows := m.appRows()
+	pos := m.cursorRow(rows)
+	if pos < 0 {
+		return
+	}
+	next := -1
+	if pos+1 < len(rows) {
+		next = rows[pos+1].index
+	} else if pos > 0 {
+		next = rows[pos-1].index
+	}
+
+	removed := m.cursor
+	m.config.Apps = append(m.config.Apps[:removed], m.config.Apps[removed+1:]...)
+	switch {
+	case next > removed:
+		m.cursor = next - 1
+	case next >= 0:
+		m.cursor = next
+	default:
+		m.cursor = 0
+	}
+}
+
+// setVisibleSelected selects or deselects every app the filter shows
+func (m *model) setVisibleSelected(selected bool) {
+	for _, r := range m.appRows() {
+		m.config.Apps[r.index].Selected = selected
+	}
+	m.trigger = "manual"
+}
+
+// updateAppFilter handles keys while the filter is being typed. Enter keeps
+// the filter, Esc clears it.
+func (m model) updateAppFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		m.filtering = false
+		m.filterInput.Blur()
+	case "esc":
+		m.filtering = false
+		m.filterInput.Blur()
+		m.filterInput.SetValue("")
+	case "up", "down":
+		if msg.String() == "up" {
+			m.moveCursor(-1)
+		} else {
+			m.moveCursor(1)
+		}
+	default:
+		var cmd tea.Cmd
+		m.filterInput, cmd = m.filterInput.Update(msg)
+		m.moveCursor(0)
+		m.followCursor()
+		return m, cmd
+	}
+	m.moveCursor(0)
+	m.followCursor()
+	return m, nil
+}
+
+// startAppFilter focuses the filter input
+func (m *model) startAppFilter() tea.Cmd {
+	if m.filterInput.Prompt == "" {
+		m.filterInput = textinput.New()
+		m.filterInput.Prompt = "/"
+		m.filterInput.Placeholder = "filter by name, process or category"
+		m.filterInput.CharLimit = 64
+	}
+	m.filtering = true
+	return m.filterInput.Focus()
+}
+
+// cycleSort advances the sort column, wrapping back to config order
+func (m *model) cycleSort() {
+	m.sortColumn = (m.sortColumn + 1) % appColumnCount
+	// Figures read best largest first
+	m.sortDesc = m.sortColumn >= colCPU && m.sortColumn <= colPIDs
+}
+
+// --- Scrolling ---
+
+// menuLayout returns whether the logo fits above the table and how many
+// app rows fit on screen, 0 for all when the terminal size is not known yet
+func (m model) menuLayout() (logo bool, rows int) {
+	if m.height == 0 {
+		return !display.screenReader, 0
+	}
+	// Padding, plus the table header, position and filter lines
+	chrome := 4 + 3 + lipgloss.Height(m.menuFooter())
+	if rows = m.height - chrome - lipgloss.Height(m.menuHeader(true)); rows >= 5 && !display.screenReader {
+		return true, rows
+	}
+	// Drop the logo before squeezing the table below a usable size
+	rows = m.height - chrome - lipgloss.Height(m.menuHeader(false))
+	if rows < 1 {
+		rows = 1
+	}
+	return false, rows
+}
+
+// followCursor scrolls the table so the cursor row is visible
+func (m *model) followCursor() {
+	_, height := m.menuLayout()
+	if height == 0 {
+		m.tableOffset = 0
+		return
+	}
+	rows := m.appRows()
+	pos := m.cursorRow(rows)
+	if pos >= 0 {
+		if pos < m.tableOffset {
+			m.tableOffset = pos
+		}
+		if pos >= m.tableOffset+height {
+			m.tableOffset = pos - height + 1
+		}
+	}
+	if max := len(rows) - height; m.tableOffset > max {
+		m.tableOffset = max
+	}
+	if m.tableOffset < 0 {
+		m.tableOffset = 0
+	}
+}
+
+// --- View ---
+
+// viewMenu renders the main menu
+func (m model) viewMenu() string {
+	logo, height := m.menuLayout()
+	return m.menuHeader(logo) + m.viewAppTable(height) + m.menuFooter()
+}
+
+// menuHeader renders the logo and system header above the table
+func (m model) menuHeader(logo bool) string {
+	var s string
+	if logo {
+		logoStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Kill)).Bold(true).MarginBottom(1)
+		s += logoStyle.Render(logoASCII) + "\n"
+		s += lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Text)).Faint(true).Render("  by tandukuda") + "\n\n"
+	}
+	return s + m.viewSystemHeader() + "\n"
+}
+
+// menuFooter renders the presets, messages and help below the table
+func (m model) menuFooter() string {
+	var s string
+	presetStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Warn)).Italic(true).MarginTop(1)
+	var presetHints []string
+	for _, p := range m.config.Presets {
+		presetHints = append(presetHints, presetHint(p))
+	}
+	if len(presetHints) > 0 {
+		s += presetStyle.Render("Presets: "+strings.Join(presetHints, "  ")) + "\n"
+	}
+
+	if m.profileMessage != "" {
+		s += "\n" + lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Highlight)).Render(m.profileMessage) + "\n"
+	}
+	if m.statusMessage != "" {
+		s += "\n" + lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Warn)).Render(m.statusMessage) + "\n"
+	}
+	if m.config.Policy.IsActive() {
+		policyLine := glyphs("🔒 System policy active: ") + m.config.Policy.Path
+		if m.config.Policy.Error != "" {
+			policyLine = glyphs("🔒 ") + m.config.Policy.Error + " (all actions disabled)"
+		}
+		s += "\n" + lipgloss.NewStyle().Faint(true).Render(policyLine) + "\n"
+	}
+
+	plainHelp(&m.help)
+	s += "\n" + m.help.View(m.keys)
+	return s
+}
+
+// viewAppTable renders the visible slice of the app table. height is the
+// number of rows to show, 0 for all.
+func (m model) viewAppTable(height int) string {
+	selected := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Select)).Bold(true)
+	unselected := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Text)).Faint(true)
+	headerStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Highlight)).Bold(true)
+	faint := lipgloss.NewStyle().Faint(true)
+
+	if len(m.config.Apps) == 0 {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Warn)).Render("No apps configured. Press '"+hint(m.keys.NewItem)+"' to add one.") + "\n"
+	}
+
+	widths := appColumnWidths
+	widths[colName] = minNameWidth
+	fixed := 6 // Cursor and checkbox
+	for c := colStatus; c < appColumnCount; c++ {
+		fixed += widths[c] + 1
+	}
+	if m.width > 0 && m.width-8-fixed > minNameWidth {
+		widths[colName] = m.width - 8 - fixed
+	}
+	if widths[colName] > 40 {
+		widths[colName] = 40
+	}
+
+	var s string
+	if display.screenReader {
+		s += headerStyle.Render(fmt.Sprintf("Apps, %d of %d shown:", len(m.appRows()), len(m.config.Apps))) + "\n"
+	}
+	header := "      "
+	for c := colName; c < appColumnCount; c++ {
+		title := appColumnTitles[c]
+		if c == m.sortColumn {
+			if m.sortDesc {
+				title += glyphs(" ▼")
+			} else {
+				title += glyphs(" ▲")
+			}
+		}
+		header += padCell(title, widths[c], c >= colCPU && c <= colRAM) + " "
+	}
+	if !display.screenReader {
+		s += headerStyle.Render(strings.TrimRight(header, " ")) + "\n"
+	}
+
+	rows := m.appRows()
+	start, end := 0, len(rows)
+	if height > 0 {
+		start = m.tableOffset
+		if start > len(rows) {
+			start = len(rows)
+		}
+		if end > start+height {
+			end = start + height
+		}
+	}
+	if len(rows) == 0 {
+		s += faint.Render("  No apps match the filter") + "\n"
+	}
+	for _, r := range rows[start:end] {
+		cursor := "  "
+		if r.index == m.cursor {
+			cursor = "> "
+		}
+		check := "[ ]"
+		if r.app.Selected {
+			check = "[x]"
+		}
+		line := cursor + check + " "
+		if display.screenReader {
+			line = cursor + r.sentence()
+		} else {
+			for c := colName; c < appColumnCount; c++ {
+				line += padCell(r.cell(c), widths[c], c >= colCPU && c <= colRAM) + " "
+			}
+		}
+		line = strings.TrimRight(line, " ")
+		if r.index == m.cursor {
+			s += selected.Render(line) + "\n"
+		} else {
+			s += unselected.Render(line) + "\n"
+		}
+	}
+
+	// Position line, doubling as the scroll indicator
+	var info []string
+	if len(rows) > end-start {
+		info = append(info, fmt.Sprintf(glyphs("%d–%d of %d"), start+1, end, len(rows)))
+		if start > 0 {
+			info = append(info, glyphs("↑ more"))
+		}
+		if end < len(rows) {
+			info = append(info, glyphs("↓ more"))
+		}
+	}
+	if m.sortColumn != colNone {
+		info = append(info, fmt.Sprintf("sorted by %s (%s: next column, %s: reverse)",
+			strings.ToLower(appColumnTitles[m.sortColumn]), hint(m.keys.Sort), hint(m.keys.SortReverse)))
+	}
+	s += faint.Render("  "+strings.Join(info, glyphs(" • "))) + "\n"
+
+	switch {
+	case m.filtering:
+		s += m.filterInput.View() + "\n"
+	case m.filterInput.Value() != "":
+		s += faint.Render(fmt.Sprintf("Filter: %s (%d of %d apps, %s: edit, esc: clear)",
+			m.filterInput.Value(), len(rows), len(m.config.Apps), hint(m.keys.Filter))) + "\n"
+	default:
+		s += faint.Render(fmt.Sprintf(glyphs("%s: filter • %s: sort"), hint(m.keys.Filter), hint(m.keys.Sort))) + "\n"
+	}
+	return s
+}
+
+// sentence describes the row in words, for screen readers
+func (r appRow) sentence() string {
+	parts := []string{"status not known yet"}
+	if r.hasStats {
+		parts[0] = strings.ReplaceAll(r.stats.Status, "_", " ")
+	}
+	if r.stats.Processes > 0 {
+		parts = append(parts, fmt.Sprintf("%d processes, CPU %.1f%%, RAM %s",
+			r.stats.Processes, r.stats.CPUPercent, formatMB(r.stats.RAMMB)))
+	}
+	if r.safety != "" {
+		parts = append(parts, r.safety)
+	}
+	if r.category != "" {
+		parts = append(parts, r.category)
+	}
+	if r.app.Selected {
+		parts = append(parts, "selected")
+	}
+	return r.app.Name + ": " + strings.Join(parts, ", ")
+}
+
+// cell returns the text of a column for the row
+func (r appRow) cell(c appColumn) string {
+	switch c {
+	case colName:
+		return r.app.Name
+	case colStatus:
+		if !r.hasStats {
+			return ellipsis()
+		}
+		return strings.ReplaceAll(r.stats.Status, "_", " ")
+	case colSafety:
+		if r.safety == "" {
+			return "-"
+		}
+		return r.safety
+	case colCPU:
+		if r.stats.Processes == 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%.1f%%", r.stats.CPUPercent)
+	case colRAM:
+		if r.stats.Processes == 0 {
+			return "-"
+		}
+		return formatMB(r.stats.RAMMB)
+	case colTrend:
+		return sparkline(seriesCPU(r.history), trendWidth, trendScale)
+	case colPIDs:
+		switch len(r.stats.PIDs) {
+		case 0:
+			return "-"
+		case 1:
+			return fmt.Sprint(r.stats.PIDs[0])
+		}
+		return fmt.Sprintf("%d +%d", r.stats.PIDs[0], len(r.stats.PIDs)-1)
+	case colCategory:
+		if r.category == "" {
+			return "-"
+		}
+		return r.category
+	}
+	return ""
+}
+
+// padCell truncates or pads s to exactly width columns
+func padCell(s string, width int, right bool) string {
+	if lipgloss.Width(s) > width {
+		s = truncate(s, width)
+	}
+	gap := strings.Repeat(" ", width-lipgloss.Width(s))
+	if right {
+		return gap + s
+	}
+	return s + gap
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// --- Audit Log ---
+//
+// Every process action is appended to a JSON-lines audit log so that it can
+// be answered later who killed what, when, and why. Unlike SessionHistory the
+// log survives restarts and is never rewritten, only rotated.
+
+// AuditConfig configures the audit log in config.yaml
+type AuditConfig struct {
+	Path       string `yaml:"path,omitempty"`
+	MaxSizeMB  int    `yaml:"max_size_mb,omitempty"`
+	MaxBackups int    `yaml:"max_backups,omitempty"`
+}
+
+// AuditTarget describes a concrete process an action matched
+type AuditTarget struct {
+	PID     int32  `json:"pid"`
+	Exe     string `json:"exe,omitempty"`
+	Cmdline string `json:"cmdline,omitempty"`
+}
+
+// AuditRecord is a single line in the audit log
+type AuditRecord struct {
+	Timestamp time.Time     `json:"timestamp"`
+	User      string        `json:"user"`
+	Host      string        `json:"host,omitempty"`
+	Action    string        `json:"action"`
+	App       string        `json:"app,omitempty"`
+	Process   string        `json:"process,omitempty"`
+	Targets   []AuditTarget `json:"targets,omitempty"`
+	Result    string        `json:"result"` // ok, error, blocked, denied
+	Error     string        `json:"error,omitempty"`
+	Trigger   string        `json:"trigger"` // manual, preset:<name>, undo
+}
+
+// AuditLogger appends records to a size-rotated log file
+type AuditLogger struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	user       string
+	host       string
+	mutex      sync.Mutex
+}
+
+// NewAuditLogger creates an audit logger, applying defaults of audit.log
+// next to config.yaml, a 5 MB file size and 5 rotated backups
+func NewAuditLogger(cfg AuditConfig) *AuditLogger {
+	al := &AuditLogger{
+		path:       configRelPath(cfg.Path),
+		maxSize:    int64(cfg.MaxSizeMB) * 1024 * 1024,
+		maxBackups: cfg.MaxBackups,
+	}
+	if al.path == "" {
+		al.path = configRelPath("audit.log")
+	}
+	if al.maxSize <= 0 {
+		al.maxSize = 5 * 1024 * 1024
+	}
+	if al.maxBackups <= 0 {
+		al.maxBackups = 5
+	}
+
+	al.user = "unknown"
+	if u, err := user.Current(); err == nil {
+		al.user = u.Username
+	}
+	al.host, _ = os.Hostname()
+	return al
+}
+
+// Log appends a record, filling in the timestamp and invoking user
+func (al *AuditLogger) Log(rec AuditRecord) error {
+	if al == nil {
+		return nil
+	}
+	rec.Timestamp = time.Now()
+	rec.User = al.user
+	rec.Host = al.host
+	if rec.Trigger == "" {
+		rec.Trigger = "manual"
+	}
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	al.mutex.Lock()
+	defer al.mutex.Unlock()
+
+	if err := al.rotateIfNeeded(int64(len(line) + 1)); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(al.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("could not open audit log: %w", err)
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// rotateIfNeeded shifts audit.log -> audit.log.1 -> ... when the next write
+// would exceed the size limit
+func (al *AuditLogger) rotateIfNeeded(next int64) error {
+	info, err := os.Stat(al.path)
+	if err != nil || info.Size()+next <= al.maxSize {
+		return nil
+	}
+
+	_ = os.Remove(fmt.Sprintf("%s.%d", al.path, al.maxBackups))
+	for i := al.maxBackups - 1; i >= 1; i-- {
+		_ = os.Rename(fmt.Sprintf("%s.%d", al.path, i), fmt.Sprintf("%s.%d", al.path, i+1))
+	}
+	if err := os.Rename(al.path, al.path+".1"); err != nil {
+		return fmt.Errorf("could not rotate audit log: %w", err)
+	}
+	return nil
+}
+
+// auditTargetsByName snapshots the processes matching a comma-separated list
+// of process names before an action touches them
+func auditTargetsByName(rawNames string) []AuditTarget {
+	procs, err := process.Processes()
+	if err != nil {
+		return nil
+	}
+
+	var targets []AuditTarget
+	for _, p := range procs {
+		name, err := p.Name()
+		if err != nil {
+			continue
+		}
+		for _, t := range strings.Split(rawNames, ",") {
+			if strings.EqualFold(name, strings.TrimSpace(t)) {
+				targets = append(targets, auditTarget(p))
+				break
+			}
+		}
+	}
+	return targets
+}
+
+// auditTargetsByPID describes a set of tracked PIDs
+func auditTargetsByPID(pids []int32) []AuditTarget {
+	targets := make([]AuditTarget, 0, len(pids))
+	for _, pid := range pids {
+		p, err := process.NewProcess(pid)
+		if err != nil {
+			targets = append(targets, AuditTarget{PID: pid})
+			continue
+		}
+		targets = append(targets, auditTarget(p))
+	}
+	return targets
+}
+
+func auditTarget(p *process.Process) AuditTarget {
+	exe, _ := p.Exe()
+	cmdline, _ := p.Cmdline()
+	return AuditTarget{PID: p.Pid, Exe: exe, Cmdline: cmdline}
+}
+
+// auditResult maps an action error to a record result and message
+func auditResult(err error) (string, string) {
+	if err != nil {
+		return "error", err.Error()
+	}
+	return "ok", ""
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Bulk Add ---
+//
+// When adding a new app, Tab in the running process picker marks processes
+// instead of picking one. Enter then opens a review of the apps that will be
+// created, with name, process name, path and safety level derived from each
+// process exactly as a single pick would. Processes already configured are
+// skipped. The new apps can be added to an existing preset, or a new one,
+// in the same step.
+
+// bulkAddState is the bulk add review's part of the model
+type bulkAddState struct {
+	apps    []AppEntry
+	skipped []string // Why marked processes will not be added
+	preset  int      // 0: none, 1..n: m.config.Presets[preset-1], n+1: new preset
+	name    textinput.Model
+	message string
+}
+
+// togglePickerMark marks or unmarks the highlighted process and moves down
+func (m *model) togglePickerMark() {
+	item, ok := m.procList.SelectedItem().(processItem)
+	if !ok {
+		return
+	}
+	if m.pickMarked[item.exe] {
+		delete(m.pickMarked, item.exe)
+	} else {
+		m.pickMarked[item.exe] = true
+	}
+	for i, it := range m.allProcs {
+		p := it.(processItem)
+		p.marked = m.pickMarked[p.exe]
+		m.allProcs[i] = p
+	}
+	index := m.procList.Index()
+	m.procList.SetItems(filterProcs(m.allProcs, m.searchInput.Value()))
+	m.procList.Select(index)
+	m.procList.CursorDown()
+}
+
+// startBulkAdd opens the review of the marked processes
+func (m model) startBulkAdd() (tea.Model, tea.Cmd) {
+	m.bulk = bulkAddState{}
+	names := map[string]bool{}
+	for _, it := range m.allProcs {
+		p := it.(processItem)
+		if !m.pickMarked[p.exe] {
+			continue
+		}
+		if i := m.appIndexForProcess(p.exe); i >= 0 {
+			m.bulk.skipped = append(m.bulk.skipped, fmt.Sprintf("%s: already configured as %s", p.exe, m.config.Apps[i].Name))
+			continue
+		}
+		name := p.name
+		for n := 2; findApp(m.config.Apps, name) >= 0 || names[strings.ToLower(name)]; n++ {
+			name = fmt.Sprintf("%s %d", p.name, n)
+		}
+		names[strings.ToLower(name)] = true
+		m.bulk.apps = append(m.bulk.apps, AppEntry{
+			Name:        name,
+			ProcessName: p.exe,
+			ExecPath:    p.path,
+			Selected:    true,
+			SafetyLevel: detectSafetyLevel(p.exe, &m.config),
+		})
+	}
+
+	m.bulk.name = textinput.New()
+	m.bulk.name.Placeholder = "Preset name"
+	m.bulk.name.CharLimit = 40
+	m.currentState = stateBulkAdd
+	return m, nil
+}
+
+func (m model) updateBulkAdd(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	newPreset := len(m.config.Presets) + 1
+	switch msg.String() {
+	case "esc":
+		// Back to the picker with the marks kept
+		m.currentState = stateProcessPicker
+		return m, nil
+	case "left", "right":
+		if msg.String() == "left" {
+			m.bulk.preset = (m.bulk.preset + newPreset) % (newPreset + 1)
+		} else {
+			m.bulk.preset = (m.bulk.preset + 1) % (newPreset + 1)
+		}
+		m.bulk.message = ""
+		if m.bulk.preset == newPreset {
+			return m, m.bulk.name.Focus()
+		}
+		m.bulk.name.Blur()
+		return m, nil
+	case "enter":
+		if len(m.bulk.apps) == 0 {
+			m.currentState = stateProcessPicker
+			return m, nil
+		}
+		if m.bulk.preset == newPreset {
+			name := strings.TrimSpace(m.bulk.name.Value())
+			if name == "" {
+				m.bulk.message = glyphs("❌ Enter a name for the new preset")
+				return m, nil
+			}
+			if findPreset(m.config.Presets, name) >= 0 {
+				m.bulk.message = fmt.Sprintf(glyphs("❌ A preset named %q already exists"), name)
+				return m, nil
+			}
+		}
+		m.commitBulkAdd()
+		return m, nil
+	}
+
+	if m.bulk.preset == newPreset {
+		var cmd tea.Cmd
+		m.bulk.name, cmd = m.bulk.name.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// commitBulkAdd creates the reviewed apps and returns to the menu
+func (m *model) commitBulkAdd() {
+	var names []string
+	for _, app := range m.bulk.apps {
+		m.config.Apps = append(m.config.Apps, app)
+		names = append(names, app.Name)
+	}
+	m.cursor = len(m.config.Apps) - 1
+
+	status := fmt.Sprintf(glyphs("✅ Added %d apps"), len(names))
+	switch p := m.bulk.preset; {
+	case p == len(m.config.Presets)+1:
+		name := strings.TrimSpace(m.bulk.name.Value())
+		m.config.Presets = append(m.config.Presets, PresetConfig{Name: name, Apps: names})
+		status += fmt.Sprintf(" to new preset %s", name)
+	case p > 0:
+		m.config.Presets[p-1].Apps = appendUnique(m.config.Presets[p-1].Apps, names)
+		status += fmt.Sprintf(" to preset %s", m.config.Presets[p-1].Name)
+	}
+
+	m.pickMarked = map[string]bool{}
+	m.saveConfig()
+	if m.saveErr == nil {
+		m.statusMessage = status
+	}
+	m.currentState = stateMenu
+	m.followCursor()
+}
+
+func (m model) viewBulkAdd() string {
+	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Highlight))
+	label := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Highlight)).Bold(true)
+	base := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Text))
+	selected := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Select)).Bold(true)
+	warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Warn))
+	faint := lipgloss.NewStyle().Faint(true)
+
+	var s string
+	s += titleStyle.Render(fmt.Sprintf("ADD %d APPS", len(m.bulk.apps))) + "\n\n"
+	if len(m.bulk.apps) == 0 {
+		s += base.Render("Nothing to add: every marked process is already configured.") + "\n"
+	} else {
+		s += label.Render(fmt.Sprintf("  %-24s %-24s %-10s %s", "Name", "Process", "Safety", "Path")) + "\n"
+		for _, app := range m.bulk.apps {
+			s += base.Render(fmt.Sprintf("  %-24s %-24s %-10s %s", truncate(app.Name, 24),
+				truncate(app.ProcessName, 24), app.SafetyLevel, orDash(app.ExecPath))) + "\n"
+		}
+	}
+	for _, skip := range m.bulk.skipped {
+		s += faint.Render("  skipped "+skip) + "\n"
+	}
+
+	if len(m.bulk.apps) > 0 {
+		choice := "(none)"
+		switch p := m.bulk.preset; {
+		case p == len(m.config.Presets)+1:
+			choice = "New preset..."
+		case p > 0:
+			choice = m.config.Presets[p-1].Name
+		}
+		s += "\n" + label.Render("Add to preset ") + selected.Render(glyphs("◀ ")+choice+glyphs(" ▶")) + "\n"
+		if m.bulk.preset == len(m.config.Presets)+1 {
+			s += m.bulk.name.View() + "\n"
+		}
+	}
+	if m.bulk.message != "" {
+		s += "\n" + warnStyle.Render(m.bulk.message) + "\n"
+	}
+
+	s += "\n" + faint.Render(glyphs("←/→: choose preset • enter: add • esc: back to picker")) + "\n"
+	return s
+}
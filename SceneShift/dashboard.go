@@ -0,0 +1,387 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/load"
+	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// --- Live Dashboard ---
+//
+// The main menu is refreshed by a background loop rather than on key
+// presses. Every refresh_interval a statsTickMsg arrives and collectStatsCmd
+// walks the process list once for all apps and reads the system totals off
+// the UI goroutine, then posts a statsMsg that View renders as is. It keeps
+// running on other screens so the resource history has no gaps. Each
+// statsMsg schedules the next tick. Restarting the loop (e.g. right after
+// an action) bumps statsID so the old loop stops.
+
+const (
+	defaultRefreshInterval = 2 * time.Second
+	minRefreshInterval     = 500 * time.Millisecond
+)
+
+// refreshInterval returns the dashboard refresh interval from the config
+func (cfg Config) refreshInterval() time.Duration {
+	if cfg.RefreshInterval <= 0 {
+		return defaultRefreshInterval
+	}
+	d := time.Duration(cfg.RefreshInterval * float64(time.Second))
+	if d < minRefreshInterval {
+		return minRefreshInterval
+	}
+	return d
+}
+
+// appStats is the live state of one app's processes
+type appStats struct {
+	Status     string // "running", "suspended" or "not_found"
+	CPUPercent float64
+	RAMMB      uint64
+	Processes  int
+	PIDs       []int32    // Running PIDs, or the recorded ones while suspended
+	Procs      []procInfo // Per-process details, only for the app in the detail view
+}
+
+// procInfo describes one process matched by an app
+type procInfo struct {
+	PID        int32
+	Name       string
+	PPID       int32
+	Exe        string
+	Cmdline    string
+	User       string
+	State      string // "running", "suspended" or "zombie"
+	CPUPercent float64
+	RSSMB      uint64
+	Started    time.Time
+}
+
+// systemStats holds the machine-wide figures for the dashboard header
+type systemStats struct {
+	CPUPercent  float64
+	RAMUsedMB   uint64
+	RAMTotalMB  uint64
+	SwapUsedMB  uint64
+	SwapTotalMB uint64
+	Load        *load.AvgStat // nil where unsupported
+}
+
+type statsTickMsg struct{ id int }
+
+type statsMsg struct {
+	id     int
+	apps   map[string]appStats // Keyed by app name
+	procs  []procInfo          // Every process, only while the explorer is open
+	system systemStats
+	at     time.Time
+}
+
+// statsRequest is what the collector needs from the model, copied so the
+// background goroutine never reads the model
+type statsRequest struct {
+	targets   []statsTarget
+	suspended map[int32]bool // Every PID SceneShift has suspended
+	all       bool           // List every process for the explorer
+}
+
+// statsTarget is one configured app to total
+type statsTarget struct {
+	name      string
+	processes []string
+	pids      []int32 // Recorded suspended PIDs
+	detail    bool    // Collect procInfo for each process
+}
+
+func statsTickCmd(id int, interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return statsTickMsg{id: id}
+	})
+}
+
+// restartStats starts a new refresh loop with an immediate refresh,
+// stopping the previous one
+func (m *model) restartStats() tea.Cmd {
+	m.statsID++
+	return m.collectStatsCmd()
+}
+
+func (m model) collectStatsCmd() tea.Cmd {
+	id := m.statsID
+	sampler := m.cpuSampler
+	req := statsRequest{suspended: map[int32]bool{}, all: m.currentState == stateProcessExplorer}
+	for _, app := range m.config.Apps {
+		app = m.config.resolveApp(app)
+		t := statsTarget{name: app.Name, detail: m.currentState == stateAppDetail && app.Name == m.detailApp}
+		for _, name := range strings.Split(app.ProcessName, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				t.processes = append(t.processes, name)
+			}
+		}
+		for pid := range app.PIDs {
+			t.pids = append(t.pids, pid)
+			req.suspended[pid] = true
+		}
+		req.targets = append(req.targets, t)
+	}
+	for pid := range m.explorer.suspended {
+		req.suspended[pid] = true
+	}
+	return func() tea.Msg {
+		apps, procs := sampler.collect(req)
+		return statsMsg{
+			id:     id,
+			apps:   apps,
+			procs:  procs,
+			system: collectSystemStats(),
+			at:     time.Now(),
+		}
+	}
+}
+
+// updateStats handles the refresh loop messages
+func (m model) updateStats(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case statsTickMsg:
+		if msg.id != m.statsID {
+			return m, nil
+		}
+		return m, m.collectStatsCmd()
+
+	case statsMsg:
+		if msg.id != m.statsID {
+			return m, nil
+		}
+		m.appStats = msg.apps
+		m.systemStats = msg.system
+		m.statsUpdated = msg.at
+		m.recordStats(msg.apps, msg.at)
+		m.explorer.procs = msg.procs
+		m.followExplorer()
+		m.followCursor() // Sorting by a figure can move the cursor row
+		return m, statsTickCmd(m.statsID, m.config.refreshInterval())
+	}
+	return m, nil
+}
+
+// --- Collection ---
+
+// cpuSampler turns cumulative process CPU times into a percentage over the
+// time since the previous refresh. gopsutil's CPUPercent averages over the
+// whole process lifetime, which barely moves for long-running apps.
+type cpuSampler struct {
+	mu   sync.Mutex
+	last map[int32]cpuSample
+}
+
+type cpuSample struct {
+	total float64 // User + system seconds
+	at    time.Time
+}
+
+func newCPUSampler() *cpuSampler {
+	return &cpuSampler{last: map[int32]cpuSample{}}
+}
+
+// collect scans the process list once and totals each target's processes.
+// With req.all it also returns every process for the explorer.
+func (s *cpuSampler) collect(req statsRequest) (map[string]appStats, []procInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make(map[string]appStats, len(req.targets))
+	procs, err := process.Processes()
+	if err != nil {
+		return result, nil
+	}
+
+	live := make(map[int32]bool, len(procs))
+	names := make(map[int32]string, len(procs))
+	byName := map[string][]*process.Process{}
+	for _, p := range procs {
+		live[p.Pid] = true
+		name, err := p.Name()
+		if err != nil {
+			continue
+		}
+		names[p.Pid] = name
+		byName[strings.ToLower(name)] = append(byName[strings.ToLower(name)], p)
+	}
+
+	now := time.Now()
+	seen := map[int32]cpuSample{}
+	cores := float64(runtime.NumCPU())
+	measured := map[int32]procInfo{}
+	measure := func(p *process.Process) procInfo {
+		if info, ok := measured[p.Pid]; ok {
+			return info
+		}
+		info := procInfo{PID: p.Pid, Name: names[p.Pid]}
+		if memInfo, err := p.MemoryInfo(); err == nil {
+			info.RSSMB = memInfo.RSS / 1024 / 1024
+		}
+		if times, err := p.Times(); err == nil {
+			sample := cpuSample{total: times.User + times.System, at: now}
+			seen[p.Pid] = sample
+			if prev, ok := s.last[p.Pid]; ok && sample.total >= prev.total {
+				if wall := now.Sub(prev.at).Seconds(); wall > 0 {
+					// Share of the whole machine, like the system header
+					info.CPUPercent = (sample.total - prev.total) / wall / cores * 100
+				}
+			}
+		}
+		measured[p.Pid] = info
+		return info
+	}
+
+	for _, t := range req.targets {
+		stats := appStats{Status: "not_found"}
+		for _, name := range t.processes {
+			for _, p := range byName[strings.ToLower(name)] {
+				info := measure(p)
+				stats.Status = "running"
+				stats.Processes++
+				stats.PIDs = append(stats.PIDs, p.Pid)
+				stats.RAMMB += info.RSSMB
+				stats.CPUPercent += info.CPUPercent
+				if t.detail {
+					describeProcess(p, &info, req.suspended, true)
+					stats.Procs = append(stats.Procs, info)
+				}
+			}
+		}
+
+		// Recorded PIDs mean the app was suspended by SceneShift
+		if len(t.pids) > 0 {
+			stats.Status = "not_found"
+			stats.PIDs = nil
+			for _, pid := range t.pids {
+				if live[pid] {
+					stats.Status = "suspended"
+					stats.PIDs = append(stats.PIDs, pid)
+				}
+			}
+		}
+		sort.Slice(stats.PIDs, func(i, j int) bool { return stats.PIDs[i] < stats.PIDs[j] })
+		sort.Slice(stats.Procs, func(i, j int) bool { return stats.Procs[i].PID < stats.Procs[j].PID })
+		result[t.name] = stats
+	}
+
+	var all []procInfo
+	if req.all {
+		all = make([]procInfo, 0, len(procs))
+		for _, p := range procs {
+			info := measure(p)
+			describeProcess(p, &info, req.suspended, false)
+			all = append(all, info)
+		}
+	}
+
+	// Forget processes that exited or are no longer tracked
+	s.last = seen
+	return result, all
+}
+
+// describeProcess fills in the parent, path and state of a process, and
+// with full also the details shown in the app detail view. A process is
+// suspended if SceneShift recorded it or the OS reports it stopped; Windows
+// does not report process state.
+func describeProcess(p *process.Process, info *procInfo, suspended map[int32]bool, full bool) {
+	info.PPID, _ = p.Ppid()
+	info.Exe, _ = p.Exe()
+	if full {
+		info.Cmdline, _ = p.Cmdline()
+		info.User, _ = p.Username()
+		if created, err := p.CreateTime(); err == nil {
+			info.Started = time.UnixMilli(created)
+		}
+	}
+
+	info.State = "running"
+	if status, err := p.Status(); err == nil && len(status) > 0 {
+		switch status[0] {
+		case process.Stop:
+			info.State = "suspended"
+		case process.Zombie:
+			info.State = "zombie"
+		}
+	}
+	if suspended[p.Pid] {
+		info.State = "suspended"
+	}
+}
+
+func collectSystemStats() systemStats {
+	var stats systemStats
+	if percents, err := cpu.Percent(0, false); err == nil && len(percents) > 0 {
+		stats.CPUPercent = percents[0]
+	}
+	if v, err := mem.VirtualMemory(); err == nil {
+		stats.RAMUsedMB = v.Used / 1024 / 1024
+		stats.RAMTotalMB = v.Total / 1024 / 1024
+	}
+	if v, err := mem.SwapMemory(); err == nil {
+		stats.SwapUsedMB = v.Used / 1024 / 1024
+		stats.SwapTotalMB = v.Total / 1024 / 1024
+	}
+	if avg, err := load.Avg(); err == nil {
+		stats.Load = avg
+	}
+	return stats
+}
+
+// --- View ---
+
+// viewSystemHeader renders the machine totals shown above the app list
+func (m model) viewSystemHeader() string {
+	label := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Highlight)).Bold(true)
+	value := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Text))
+	warn := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Warn))
+	faint := lipgloss.NewStyle().Faint(true)
+
+	if m.statsUpdated.IsZero() {
+		return faint.Render("Collecting system stats...") + "\n"
+	}
+	sys := m.systemStats
+
+	// Highlight anything above 85% so a busy machine stands out
+	level := func(used, total float64) lipgloss.Style {
+		if total > 0 && used/total > 0.85 {
+			return warn
+		}
+		return value
+	}
+
+	parts := []string{
+		label.Render("CPU ") + level(sys.CPUPercent, 100).Render(fmt.Sprintf("%.0f%%", sys.CPUPercent)),
+		label.Render("RAM ") + level(float64(sys.RAMUsedMB), float64(sys.RAMTotalMB)).Render(
+			fmt.Sprintf("%s / %s", formatMB(sys.RAMUsedMB), formatMB(sys.RAMTotalMB))),
+	}
+	if sys.SwapTotalMB > 0 {
+		parts = append(parts, label.Render("Swap ")+level(float64(sys.SwapUsedMB), float64(sys.SwapTotalMB)).Render(
+			fmt.Sprintf("%s / %s", formatMB(sys.SwapUsedMB), formatMB(sys.SwapTotalMB))))
+	}
+	if l := sys.Load; l != nil && (l.Load1 > 0 || l.Load5 > 0 || l.Load15 > 0) {
+		parts = append(parts, label.Render("Load ")+value.Render(fmt.Sprintf("%.2f %.2f %.2f", l.Load1, l.Load5, l.Load15)))
+	}
+	parts = append(parts, faint.Render(fmt.Sprintf(glyphs("↻ %s"), m.config.refreshInterval())))
+	return strings.Join(parts, faint.Render(glyphs("  │  "))) + "\n"
+}
+
+// formatMB renders a size in MB, switching to GB above 1024
+func formatMB(mb uint64) string {
+	if mb >= 1024 {
+		return fmt.Sprintf("%.1f GB", float64(mb)/1024)
+	}
+	return fmt.Sprintf("%d MB", mb)
+}
@@ -0,0 +1,557 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Executable Discovery ---
+//
+// Finds where a program is installed from its process name, so exec_path
+// can be filled in without the program running. Sources, in order:
+//
+//	Windows: App Paths registry keys, Start Menu shortcuts, PATH
+//	Linux:   .desktop launchers, PATH
+//
+// Only paths that exist are offered.
+
+// execCandidate is an installed executable matching a process name
+type execCandidate struct {
+	Path   string
+	Source string // Where it was found, e.g. "Start Menu"
+}
+
+// execIndex maps lowercase executable names to the shortcuts and launchers
+// pointing at them, so many apps can be looked up with one scan
+type execIndex map[string][]execCandidate
+
+type discoveryMsg struct {
+	id      int                        // Search this answers; older ones are dropped
+	results map[string][]execCandidate // Keyed by process_name as configured
+}
+
+// discoverCmd looks up executables for each process_name in the background
+func (m *model) discoverCmd(processNames []string) tea.Cmd {
+	m.discoveryID++
+	m.discovering = true
+	id, cfg := m.discoveryID, m.config
+	return func() tea.Msg {
+		index := buildExecIndex()
+		results := make(map[string][]execCandidate, len(processNames))
+		for _, names := range processNames {
+			results[names] = index.find(cfg.expand(names))
+		}
+		return discoveryMsg{id: id, results: results}
+	}
+}
+
+// buildExecIndex scans the Start Menu or .desktop launchers
+func buildExecIndex() execIndex {
+	index := execIndex{}
+	if runtime.GOOS == "windows" {
+		for _, dir := range startMenuDirs() {
+			filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+				if err != nil || d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".lnk") {
+					return nil
+				}
+				if target := shortcutTarget(path); target != "" {
+					index.add(target, "Start Menu")
+				}
+				return nil
+			})
+		}
+		return index
+	}
+
+	for _, dir := range desktopDirs() {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if strings.HasSuffix(e.Name(), ".desktop") {
+				if target := desktopExec(filepath.Join(dir, e.Name())); target != "" {
+					index.add(target, "Launcher")
+				}
+			}
+		}
+	}
+	return index
+}
+
+func (index execIndex) add(path, source string) {
+	name := strings.ToLower(filepath.Base(path))
+	index[name] = append(index[name], execCandidate{Path: path, Source: source})
+}
+
+// find returns the installed executables for a comma separated list of
+// process names, best match first
+func (index execIndex) find(processNames string) []execCandidate {
+	var found []execCandidate
+	for _, name := range strings.Split(processNames, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		for _, path := range appPathsLookup(name) {
+			found = append(found, execCandidate{Path: path, Source: "App Paths"})
+		}
+		for indexed, candidates := range index {
+			if matchesProcessName(indexed, name) {
+				found = append(found, candidates...)
+			}
+		}
+		for _, path := range searchPath(name) {
+			found = append(found, execCandidate{Path: path, Source: "PATH"})
+		}
+	}
+	return uniqueExisting(found)
+}
+
+// matchesProcessName compares an executable name with a process name.
+// Linux truncates process names to 15 characters.
+func matchesProcessName(exe, process string) bool {
+	if strings.EqualFold(exe, process) {
+		return true
+	}
+	return runtime.GOOS == "linux" && len(process) == 15 && strings.HasPrefix(strings.ToLower(exe), strings.ToLower(process))
+}
+
+// uniqueExisting drops duplicates and paths that no longer exist
+func uniqueExisting(candidates []execCandidate) []execCandidate {
+	seen := map[string]bool{}
+	var out []execCandidate
+	for _, c := range candidates {
+		id := filepath.Clean(c.Path)
+		if runtime.GOOS == "windows" {
+			id = strings.ToLower(id)
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if info, err := os.Stat(c.Path); err == nil && !info.IsDir() {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// searchPath returns every match for name on PATH, not just the first
+func searchPath(name string) []string {
+	names := []string{name}
+	if runtime.GOOS == "windows" && filepath.Ext(name) == "" {
+		names = nil
+		for _, ext := range filepath.SplitList(os.Getenv("PATHEXT")) {
+			names = append(names, name+strings.ToLower(ext))
+		}
+	}
+
+	var found []string
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			continue
+		}
+		for _, n := range names {
+			path := filepath.Join(dir, n)
+			info, err := os.Stat(path)
+			if err != nil || info.IsDir() {
+				continue
+			}
+			if runtime.GOOS != "windows" && info.Mode()&0111 == 0 {
+				continue
+			}
+			found = append(found, path)
+		}
+	}
+	return found
+}
+
+// --- Start Menu Shortcuts ---
+
+func startMenuDirs() []string {
+	var dirs []string
+	for _, env := range []string{"APPDATA", "ProgramData"} {
+		if root := os.Getenv(env); root != "" {
+			dirs = append(dirs, filepath.Join(root, "Microsoft", "Windows", "Start Menu", "Programs"))
+		}
+	}
+	return dirs
+}
+
+// shortcutTarget reads the local target path of a .lnk file, or "" if it
+// has none (e.g. shortcuts to URLs or advertised installer entries)
+func shortcutTarget(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil || len(data) < 0x4C || binary.LittleEndian.Uint32(data) != 0x4C {
+		return ""
+	}
+	flags := binary.LittleEndian.Uint32(data[0x14:])
+	const (
+		hasTargetIDList = 1 << 0
+		hasLinkInfo     = 1 << 1
+	)
+
+	offset := 0x4C
+	if flags&hasTargetIDList != 0 {
+		if len(data) < offset+2 {
+			return ""
+		}
+		offset += 2 + int(binary.LittleEndian.Uint16(data[offset:]))
+	}
+	if flags&hasLinkInfo == 0 || len(data) < offset+0x1C {
+		return ""
+	}
+
+	info := data[offset:]
+	size := int(binary.LittleEndian.Uint32(info))
+	if size < 0x1C || size > len(info) {
+		return ""
+	}
+	info = info[:size]
+	headerSize := binary.LittleEndian.Uint32(info[4:])
+	const volumeIDAndLocalBasePath = 1 << 0
+	if binary.LittleEndian.Uint32(info[8:])&volumeIDAndLocalBasePath == 0 {
+		return ""
+	}
+	if headerSize >= 0x24 && len(info) >= 0x24 {
+		if target := utf16String(info, int(binary.LittleEndian.Uint32(info[0x1C:]))); target != "" {
+			return target
+		}
+	}
+	return ansiString(info, int(binary.LittleEndian.Uint32(info[0x10:])))
+}
+
+// ansiString reads a NUL-terminated single-byte string
+func ansiString(data []byte, offset int) string {
+	if offset <= 0 || offset >= len(data) {
+		return ""
+	}
+	end := offset
+	for end < len(data) && data[end] != 0 {
+		end++
+	}
+	runes := make([]rune, 0, end-offset)
+	for _, b := range data[offset:end] {
+		runes = append(runes, rune(b))
+	}
+	return string(runes)
+}
+
+// utf16String reads a NUL-terminated UTF-16LE string
+func utf16String(data []byte, offset int) string {
+	if offset <= 0 || offset >= len(data) {
+		return ""
+	}
+	var units []uint16
+	for i := offset; i+1 < len(data); i += 2 {
+		u := binary.LittleEndian.Uint16(data[i:])
+		if u == 0 {
+			break
+		}
+		units = append(units, u)
+	}
+	return string(utf16.Decode(units))
+}
+
+// --- Desktop Launchers ---
+
+func desktopDirs() []string {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			dataHome = filepath.Join(home, ".local", "share")
+		}
+	}
+	dataDirs := os.Getenv("XDG_DATA_DIRS")
+	if dataDirs == "" {
+		dataDirs = "/usr/local/share:/usr/share"
+	}
+
+	var dirs []string
+	if dataHome != "" {
+		dirs = append(dirs, filepath.Join(dataHome, "applications"))
+		dirs = append(dirs, filepath.Join(dataHome, "flatpak", "exports", "share", "applications"))
+	}
+	for _, dir := range filepath.SplitList(dataDirs) {
+		dirs = append(dirs, filepath.Join(dir, "applications"))
+	}
+	return append(dirs, "/var/lib/flatpak/exports/share/applications", "/var/lib/snapd/desktop/applications")
+}
+
+// desktopExec returns the program a .desktop launcher runs, resolved
+// against PATH
+func desktopExec(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	inEntry := false
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			inEntry = line == "[Desktop Entry]"
+			continue
+		}
+		if !inEntry || !strings.HasPrefix(line, "Exec=") {
+			continue
+		}
+
+		args := splitArgs(strings.TrimPrefix(line, "Exec="))
+		// Skip "env VAR=value" wrappers
+		for len(args) > 0 && (args[0] == "env" || strings.Contains(args[0], "=")) {
+			args = args[1:]
+		}
+		if len(args) == 0 {
+			return ""
+		}
+		if filepath.IsAbs(args[0]) {
+			return args[0]
+		}
+		if found := searchPath(args[0]); len(found) > 0 {
+			return found[0]
+		}
+		return ""
+	}
+	return ""
+}
+
+// --- Choosing a Path in the App Editor ---
+
+// startExecDiscovery looks up the process typed in the app editor
+func (m model) startExecDiscovery() (tea.Model, tea.Cmd) {
+	m.discoverFor = strings.TrimSpace(m.inputs[1].Value())
+	m.execCandidates = nil
+	m.execCursor = 0
+	m.currentState = stateExecPicker
+	if m.discoverFor == "" {
+		return m, nil
+	}
+	return m, m.discoverCmd([]string{m.discoverFor})
+}
+
+func (m model) updateExecPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.execCursor > 0 {
+			m.execCursor--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.execCursor < len(m.execCandidates)-1 {
+			m.execCursor++
+		}
+	case msg.String() == "enter":
+		if m.execCursor < len(m.execCandidates) {
+			m.inputs[2].SetValue(m.execCandidates[m.execCursor].Path)
+		}
+		m.currentState = stateAppEdit
+	case msg.String() == "esc":
+		m.currentState = stateAppEdit
+	}
+	return m, nil
+}
+
+func (m model) viewExecPicker() string {
+	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Select)).Bold(true).Underline(true)
+	selected := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Select)).Bold(true)
+	unselected := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Text)).Faint(true)
+	warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Warn))
+
+	var s string
+	s += titleStyle.Render(glyphs("📂 INSTALLED LOCATIONS: ")+m.discoverFor) + "\n\n"
+	switch {
+	case m.discoverFor == "":
+		s += warnStyle.Render("Enter a process name first.") + "\n"
+	case m.discovering:
+		s += warnStyle.Render("Searching installed programs...") + "\n"
+	case len(m.execCandidates) == 0:
+		s += warnStyle.Render("No installed executable found. Type the path by hand.") + "\n"
+	}
+	for i, c := range m.execCandidates {
+		line := fmt.Sprintf("%s  (%s)", c.Path, c.Source)
+		if i == m.execCursor {
+			s += selected.Render("> "+line) + "\n"
+		} else {
+			s += unselected.Render("  "+line) + "\n"
+		}
+	}
+	s += "\n" + lipgloss.NewStyle().Faint(true).Render(glyphs("Enter: Use path • Esc: Back")) + "\n"
+	return s
+}
+
+// --- Repairing Paths in Bulk ---
+
+// pathRepair is a proposed new exec_path for one app
+type pathRepair struct {
+	app        string // App name, so a reload cannot misapply it
+	current    string
+	candidates []execCandidate
+	choice     int
+	apply      bool
+}
+
+// brokenPathApps returns the apps whose exec_path is unset or missing
+func (m model) brokenPathApps() []pathRepair {
+	var repairs []pathRepair
+	for _, app := range m.config.Apps {
+		if app.ExecPath != "" {
+			if _, err := os.Stat(m.config.expand(app.ExecPath)); err == nil {
+				continue
+			}
+		}
+		repairs = append(repairs, pathRepair{app: app.Name, current: app.ExecPath})
+	}
+	return repairs
+}
+
+// startPathRepair searches for every app with a broken exec_path
+func (m model) startPathRepair() (tea.Model, tea.Cmd) {
+	m.repairs = m.brokenPathApps()
+	m.repairCursor = 0
+	m.currentState = stateRepairPaths
+	if len(m.repairs) == 0 {
+		return m, nil
+	}
+
+	var names []string
+	for _, r := range m.repairs {
+		if i := findApp(m.config.Apps, r.app); i >= 0 {
+			names = append(names, m.config.Apps[i].ProcessName)
+		}
+	}
+	return m, m.discoverCmd(names)
+}
+
+// applyDiscovery fills in the results of a background search
+func (m model) applyDiscovery(msg discoveryMsg) (tea.Model, tea.Cmd) {
+	if msg.id != m.discoveryID {
+		return m, nil
+	}
+	m.discovering = false
+	switch m.currentState {
+	case stateExecPicker:
+		m.execCandidates = msg.results[m.discoverFor]
+	case stateRepairPaths:
+		for i := range m.repairs {
+			if j := findApp(m.config.Apps, m.repairs[i].app); j >= 0 {
+				m.repairs[i].candidates = msg.results[m.config.Apps[j].ProcessName]
+				m.repairs[i].apply = len(m.repairs[i].candidates) > 0
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m model) updateRepairPaths(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.String() == "esc":
+		m.currentState = stateMenu
+	case m.discovering:
+		// Wait for the search to finish
+	case key.Matches(msg, m.keys.Up):
+		if m.repairCursor > 0 {
+			m.repairCursor--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.repairCursor < len(m.repairs)-1 {
+			m.repairCursor++
+		}
+	case key.Matches(msg, m.keys.Toggle):
+		if r := m.currentRepair(); r != nil && len(r.candidates) > 0 {
+			r.apply = !r.apply
+		}
+	case msg.String() == "left", msg.String() == "right":
+		if r := m.currentRepair(); r != nil && len(r.candidates) > 1 {
+			step := 1
+			if msg.String() == "left" {
+				step = len(r.candidates) - 1
+			}
+			r.choice = (r.choice + step) % len(r.candidates)
+		}
+	case msg.String() == "enter":
+		fixed := 0
+		for _, r := range m.repairs {
+			i := findApp(m.config.Apps, r.app)
+			if !r.apply || i < 0 {
+				continue
+			}
+			m.config.Apps[i].ExecPath = r.candidates[r.choice].Path
+			fixed++
+		}
+		if fixed > 0 {
+			m.saveConfig()
+			m.statusMessage = fmt.Sprintf(glyphs("🔧 Repaired %d app path(s)"), fixed)
+		}
+		m.currentState = stateMenu
+	}
+	return m, nil
+}
+
+func (m *model) currentRepair() *pathRepair {
+	if m.repairCursor < len(m.repairs) {
+		return &m.repairs[m.repairCursor]
+	}
+	return nil
+}
+
+func (m model) viewRepairPaths() string {
+	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Select)).Bold(true).Underline(true)
+	selected := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Select)).Bold(true)
+	unselected := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Text)).Faint(true)
+	restoreStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Restore))
+	warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Warn))
+
+	var s string
+	s += titleStyle.Render(glyphs("🔧 REPAIR APP PATHS")) + "\n\n"
+	if len(m.repairs) == 0 {
+		s += restoreStyle.Render(glyphs("✅ Every app has a working executable path")) + "\n"
+		s += "\n" + lipgloss.NewStyle().Faint(true).Render("Esc: Back") + "\n"
+		return s
+	}
+	if m.discovering {
+		s += warnStyle.Render("Searching installed programs...") + "\n\n"
+	}
+
+	for i, r := range m.repairs {
+		check := "[ ]"
+		if r.apply {
+			check = "[x]"
+		}
+		current := "not set"
+		if r.current != "" {
+			current = "missing: " + r.current
+		}
+		line := fmt.Sprintf("%s %s  (%s)", check, r.app, current)
+		if i == m.repairCursor {
+			s += selected.Render("> "+line) + "\n"
+		} else {
+			s += unselected.Render("  "+line) + "\n"
+		}
+
+		switch {
+		case m.discovering:
+		case len(r.candidates) == 0:
+			s += warnStyle.Render("      no installed executable found") + "\n"
+		default:
+			c := r.candidates[r.choice]
+			detail := fmt.Sprintf(glyphs("      → %s (%s)"), c.Path, c.Source)
+			if len(r.candidates) > 1 {
+				detail += fmt.Sprintf(" [%d/%d]", r.choice+1, len(r.candidates))
+			}
+			s += restoreStyle.Render(detail) + "\n"
+		}
+	}
+
+	s += "\n" + lipgloss.NewStyle().Faint(true).Render(hint(m.keys.Toggle)+glyphs(": Toggle • ←/→: Other match • Enter: Apply checked • Esc: Cancel")) + "\n"
+	return s
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package main
+
+// appPathsLookup has no equivalent outside Windows
+func appPathsLookup(exe string) []string {
+	return nil
+}
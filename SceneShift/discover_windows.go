@@ -0,0 +1,45 @@
+package main
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+const appPathsKey = `SOFTWARE\Microsoft\Windows\CurrentVersion\App Paths\`
+
+// appPathsLookup reads the App Paths registry entries for an executable
+// name, which installers register so Win+R can find the program
+func appPathsLookup(exe string) []string {
+	keyPath, err := syscall.UTF16PtrFromString(appPathsKey + exe)
+	if err != nil {
+		return nil
+	}
+
+	var found []string
+	for _, root := range []syscall.Handle{syscall.HKEY_CURRENT_USER, syscall.HKEY_LOCAL_MACHINE} {
+		var key syscall.Handle
+		if syscall.RegOpenKeyEx(root, keyPath, 0, syscall.KEY_READ, &key) != nil {
+			continue
+		}
+		var valType, size uint32
+		if syscall.RegQueryValueEx(key, nil, nil, &valType, nil, &size) == nil && size > 0 {
+			buf := make([]uint16, size/2+1)
+			if syscall.RegQueryValueEx(key, nil, nil, &valType, (*byte)(unsafe.Pointer(&buf[0])), &size) == nil {
+				path := trimQuotes(syscall.UTF16ToString(buf))
+				if valType == syscall.REG_EXPAND_SZ {
+					path = (&Config{}).expand(path)
+				}
+				found = append(found, path)
+			}
+		}
+		syscall.RegCloseKey(key)
+	}
+	return found
+}
+
+func trimQuotes(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,523 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Process Explorer ---
+//
+// A live view of every running process, opened from the main menu. The
+// refresh loop lists all processes while the explorer is open (see
+// statsRequest.all), so figures update in place. Processes are shown as a
+// parent/child tree whose branches can be collapsed; typing a search shows
+// the matches as a flat list instead. Siblings, or the matches, are sorted
+// by the chosen column.
+//
+// Kill, suspend and resume work on any process after a confirmation, with
+// the same protection and policy checks as the main actions. A process can
+// also be added as an app, pre-filling its process name and path.
+
+type procSort int
+
+const (
+	procSortName procSort = iota
+	procSortPID
+	procSortCPU
+	procSortRAM
+	procSortCount
+)
+
+// explorerState is the process explorer's part of the model
+type explorerState struct {
+	procs     []procInfo
+	collapsed map[int32]bool
+	pid       int32 // Selected process
+	offset    int   // First row shown
+	sort      procSort
+	desc      bool
+	searching bool // Typing in search
+	search    textinput.Model
+	confirm   string // Action awaiting confirmation for pid
+	message   string
+
+	// Processes suspended here that do not belong to a configured app,
+	// which track their own in AppEntry.PIDs
+	suspended map[int32]bool
+}
+
+// explorerRow is one process as shown in the explorer
+type explorerRow struct {
+	info        procInfo
+	prefix      string // Tree branches drawn before the name
+	hasChildren bool
+}
+
+// startProcessExplorer opens the explorer and starts listing processes
+func (m model) startProcessExplorer() (tea.Model, tea.Cmd) {
+	m.explorer.procs = nil
+	m.explorer.confirm = ""
+	m.explorer.message = ""
+	m.explorer.offset = 0
+	if m.explorer.collapsed == nil {
+		m.explorer.collapsed = map[int32]bool{}
+	}
+	m.currentState = stateProcessExplorer
+	return m, m.restartStats()
+}
+
+// explorerRows returns the processes in display order
+func (m model) explorerRows() []explorerRow {
+	procs := m.explorer.procs
+	less := func(a, b procInfo) bool {
+		c := compareProcs(a, b, m.explorer.sort)
+		if c == 0 {
+			c = sign(int(a.PID - b.PID))
+		}
+		if m.explorer.desc {
+			return c > 0
+		}
+		return c < 0
+	}
+
+	if query := strings.ToLower(strings.TrimSpace(m.explorer.search.Value())); query != "" {
+		var rows []explorerRow
+		for _, p := range procs {
+			if strings.Contains(strings.ToLower(p.Name), query) ||
+				strings.Contains(strings.ToLower(p.Exe), query) ||
+				strings.Contains(fmt.Sprint(p.PID), query) {
+				rows = append(rows, explorerRow{info: p})
+			}
+		}
+		sort.SliceStable(rows, func(i, j int) bool { return less(rows[i].info, rows[j].info) })
+		return rows
+	}
+
+	present := make(map[int32]bool, len(procs))
+	for _, p := range procs {
+		present[p.PID] = true
+	}
+	children := map[int32][]procInfo{}
+	var roots []procInfo
+	for _, p := range procs {
+		if p.PPID == p.PID || !present[p.PPID] {
+			roots = append(roots, p)
+		} else {
+			children[p.PPID] = append(children[p.PPID], p)
+		}
+	}
+	sort.SliceStable(roots, func(i, j int) bool { return less(roots[i], roots[j]) })
+
+	var rows []explorerRow
+	visited := map[int32]bool{}
+	// Processes under a collapsed one are walked hidden, so they are not
+	// mistaken for the unreachable ones below
+	var walk func(p procInfo, prefix, indent string, hidden bool)
+	walk = func(p procInfo, prefix, indent string, hidden bool) {
+		if visited[p.PID] {
+			return // PID reuse can make a parent its own descendant
+		}
+		visited[p.PID] = true
+		kids := children[p.PID]
+		if !hidden {
+			rows = append(rows, explorerRow{info: p, prefix: prefix, hasChildren: len(kids) > 0})
+		}
+		hidden = hidden || m.explorer.collapsed[p.PID]
+		sort.SliceStable(kids, func(i, j int) bool { return less(kids[i], kids[j]) })
+		for i, kid := range kids {
+			if i == len(kids)-1 {
+				walk(kid, indent+glyphs("└─ "), indent+"   ", hidden)
+			} else {
+				walk(kid, indent+glyphs("├─ "), indent+glyphs("│  "), hidden)
+			}
+		}
+	}
+	for _, p := range roots {
+		walk(p, "", "", false)
+	}
+
+	// PID reuse can also close a loop of parents with no root above it; each
+	// such loop is shown from its first process in sort order
+	rest := make([]procInfo, 0, len(procs)-len(visited))
+	for _, p := range procs {
+		if !visited[p.PID] {
+			rest = append(rest, p)
+		}
+	}
+	sort.SliceStable(rest, func(i, j int) bool { return less(rest[i], rest[j]) })
+	for _, p := range rest {
+		walk(p, "", "", false)
+	}
+	return rows
+}
+
+// compareProcs orders two processes by a column, returning -1, 0 or 1
+func compareProcs(a, b procInfo, col procSort) int {
+	switch col {
+	case procSortPID:
+		return sign(int(a.PID - b.PID))
+	case procSortCPU:
+		switch {
+		case a.CPUPercent < b.CPUPercent:
+			return -1
+		case a.CPUPercent > b.CPUPercent:
+			return 1
+		}
+		return 0
+	case procSortRAM:
+		return sign(int(a.RSSMB) - int(b.RSSMB))
+	}
+	return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+}
+
+// explorerCursor returns the position of the selected process in rows,
+// falling back to the first row once it has exited or is hidden
+func (m model) explorerCursor(rows []explorerRow) int {
+	if len(rows) == 0 {
+		return -1
+	}
+	for i, r := range rows {
+		if r.info.PID == m.explorer.pid {
+			return i
+		}
+	}
+	return 0
+}
+
+// explorerHeight returns how many process rows fit on screen, 0 for all
+func (m model) explorerHeight() int {
+	if m.height == 0 {
+		return 0
+	}
+	// Padding, title, column header, position, selected path, message, hints
+	rows := m.height - 4 - 2 - 1 - 1 - 3 - 2 - 2
+	if rows < 3 {
+		rows = 3
+	}
+	return rows
+}
+
+// followExplorer scrolls the explorer so the selected process is visible
+func (m *model) followExplorer() {
+	if m.currentState != stateProcessExplorer {
+		return
+	}
+	rows := m.explorerRows()
+	pos := m.explorerCursor(rows)
+	if pos >= 0 {
+		m.explorer.pid = rows[pos].info.PID
+	}
+	height := m.explorerHeight()
+	if height == 0 {
+		m.explorer.offset = 0
+		return
+	}
+	if pos >= 0 {
+		if pos < m.explorer.offset {
+			m.explorer.offset = pos
+		}
+		if pos >= m.explorer.offset+height {
+			m.explorer.offset = pos - height + 1
+		}
+	}
+	if max := len(rows) - height; m.explorer.offset > max {
+		m.explorer.offset = max
+	}
+	if m.explorer.offset < 0 {
+		m.explorer.offset = 0
+	}
+}
+
+func (m model) updateProcessExplorer(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	rows := m.explorerRows()
+	pos := m.explorerCursor(rows)
+
+	if m.explorer.searching {
+		switch msg.String() {
+		case "enter":
+			m.explorer.searching = false
+			m.explorer.search.Blur()
+		case "esc":
+			m.explorer.searching = false
+			m.explorer.search.Blur()
+			m.explorer.search.SetValue("")
+		default:
+			var cmd tea.Cmd
+			m.explorer.search, cmd = m.explorer.search.Update(msg)
+			m.followExplorer()
+			return m, cmd
+		}
+		m.followExplorer()
+		return m, nil
+	}
+
+	if m.explorer.confirm != "" {
+		switch msg.String() {
+		case "enter", "y":
+			mode := m.explorer.confirm
+			m.explorer.confirm = ""
+			if pos >= 0 {
+				p := rows[pos].info
+				m.explorer.message = m.processAction(mode, p, m.appForProcess(p.Name))
+			}
+			return m, m.restartStats()
+		case "esc", "n":
+			m.explorer.confirm = ""
+		}
+		return m, nil
+	}
+
+	move := func(to int) {
+		if len(rows) == 0 {
+			return
+		}
+		if to < 0 {
+			to = 0
+		}
+		if to >= len(rows) {
+			to = len(rows) - 1
+		}
+		m.explorer.pid = rows[to].info.PID
+	}
+
+	switch {
+	case msg.String() == "esc" && m.explorer.search.Value() != "":
+		m.explorer.search.SetValue("")
+	case msg.String() == "esc", key.Matches(msg, m.keys.Quit):
+		m.currentState = stateMenu
+		m.explorer.procs = nil
+		return m, nil
+	case key.Matches(msg, m.keys.Up):
+		move(pos - 1)
+	case key.Matches(msg, m.keys.Down):
+		move(pos + 1)
+	case msg.String() == "pgup":
+		move(pos - m.explorerHeight())
+	case msg.String() == "pgdown":
+		move(pos + m.explorerHeight())
+	case msg.String() == "home":
+		move(0)
+	case msg.String() == "end":
+		move(len(rows) - 1)
+
+	case msg.String() == "left" && pos >= 0:
+		// Collapse, or jump to the parent when already collapsed
+		r := rows[pos]
+		if r.hasChildren && !m.explorer.collapsed[r.info.PID] {
+			m.explorer.collapsed[r.info.PID] = true
+		} else {
+			for i := pos - 1; i >= 0; i-- {
+				if rows[i].info.PID == r.info.PPID {
+					move(i)
+					break
+				}
+			}
+		}
+	case msg.String() == "right" && pos >= 0:
+		delete(m.explorer.collapsed, rows[pos].info.PID)
+	case key.Matches(msg, m.keys.Toggle) && pos >= 0:
+		pid := rows[pos].info.PID
+		if m.explorer.collapsed[pid] {
+			delete(m.explorer.collapsed, pid)
+		} else if rows[pos].hasChildren {
+			m.explorer.collapsed[pid] = true
+		}
+
+	case key.Matches(msg, m.keys.Sort):
+		m.explorer.sort = (m.explorer.sort + 1) % procSortCount
+		// Figures read best largest first
+		m.explorer.desc = m.explorer.sort == procSortCPU || m.explorer.sort == procSortRAM
+	case key.Matches(msg, m.keys.SortReverse):
+		m.explorer.desc = !m.explorer.desc
+	case key.Matches(msg, m.keys.Filter):
+		if m.explorer.search.Prompt == "" {
+			m.explorer.search = textinput.New()
+			m.explorer.search.Prompt = "/"
+			m.explorer.search.Placeholder = "search by name, path or PID"
+			m.explorer.search.CharLimit = 64
+		}
+		m.explorer.searching = true
+		return m, m.explorer.search.Focus()
+
+	case key.Matches(msg, m.keys.Kill) && pos >= 0:
+		m.explorer.confirm, m.explorer.message = "kill", ""
+	case key.Matches(msg, m.keys.Suspend) && pos >= 0:
+		m.explorer.confirm, m.explorer.message = "suspend", ""
+	case key.Matches(msg, m.keys.Resume) && pos >= 0:
+		m.explorer.confirm, m.explorer.message = "resume", ""
+
+	case key.Matches(msg, m.keys.NewItem) && pos >= 0:
+		return m.addProcessAsApp(rows[pos].info)
+	}
+
+	m.followExplorer()
+	return m, nil
+}
+
+// addProcessAsApp opens the app editor pre-filled from a process
+func (m model) addProcessAsApp(p procInfo) (tea.Model, tea.Cmd) {
+	m.isNewItem = true
+	m.focusIndex = 0
+	m.setupAppInputs()
+	m.inputs[0].SetValue(friendlyName(p.Name))
+	m.inputs[1].SetValue(p.Name)
+	m.inputs[2].SetValue(p.Exe)
+	m.currentState = stateAppEdit
+	m.explorer.procs = nil
+	if p.Exe == "" {
+		// Path hidden, e.g. an elevated process; look for the install
+		return m.startExecDiscovery()
+	}
+	return m, nil
+}
+
+// friendlyName turns an executable name into an app name, e.g. "Discord"
+func friendlyName(exe string) string {
+	return strings.Title(strings.TrimSuffix(exe, filepath.Ext(exe)))
+}
+
+// appForProcess returns the configured app a process name belongs to
+func (m *model) appForProcess(name string) *AppEntry {
+	if i := m.appIndexForProcess(name); i >= 0 {
+		return &m.config.Apps[i]
+	}
+	return nil
+}
+
+// appIndexForProcess returns the index of the app that runs a process name,
+// -1 if none
+func (m model) appIndexForProcess(name string) int {
+	for i, app := range m.config.Apps {
+		for _, t := range strings.Split(m.config.expand(app.ProcessName), ",") {
+			if strings.EqualFold(strings.TrimSpace(t), name) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func (m model) viewProcessExplorer() string {
+	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Select)).Bold(true).Underline(true)
+	headerStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Highlight)).Bold(true)
+	base := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Text))
+	selected := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Select)).Bold(true)
+	unselected := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Text)).Faint(true)
+	warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Warn)).Bold(true)
+	faint := lipgloss.NewStyle().Faint(true)
+
+	var s string
+	s += titleStyle.Render("PROCESS EXPLORER") + "\n\n"
+	if m.explorer.procs == nil {
+		return s + faint.Render("Listing processes...") + "\n"
+	}
+
+	nameWidth := 36
+	if m.width > 0 {
+		// Cursor, PID, CPU, RAM, state and app columns take about 62
+		nameWidth = m.width - 8 - 62
+		if nameWidth < 20 {
+			nameWidth = 20
+		}
+		if nameWidth > 60 {
+			nameWidth = 60
+		}
+	}
+
+	title := func(col procSort, text string) string {
+		if m.explorer.sort == col {
+			if m.explorer.desc {
+				return text + glyphs(" ▼")
+			}
+			return text + glyphs(" ▲")
+		}
+		return text
+	}
+	s += headerStyle.Render(fmt.Sprintf("  %s %8s %8s %10s  %-10s %s",
+		padCell(title(procSortName, "Name"), nameWidth, false), title(procSortPID, "PID"),
+		title(procSortCPU, "CPU"), title(procSortRAM, "RAM"), "State", "App")) + "\n"
+
+	rows := m.explorerRows()
+	pos := m.explorerCursor(rows)
+	start, end := 0, len(rows)
+	if height := m.explorerHeight(); height > 0 {
+		start = m.explorer.offset
+		if start > len(rows) {
+			start = len(rows)
+		}
+		if end > start+height {
+			end = start + height
+		}
+	}
+	if len(rows) == 0 {
+		s += faint.Render("  No processes match the search") + "\n"
+	}
+	for i := start; i < end; i++ {
+		r := rows[i]
+		cursor := "  "
+		if i == pos {
+			cursor = "> "
+		}
+		marker := "  "
+		if r.hasChildren {
+			marker = glyphs("▾ ")
+			if m.explorer.collapsed[r.info.PID] {
+				marker = glyphs("▸ ")
+			}
+		}
+		note := ""
+		if app := m.appForProcess(r.info.Name); app != nil {
+			note = app.Name
+		}
+		if m.config.isProtected(r.info.Name) {
+			note = strings.TrimSpace(glyphs("🛡️ ") + note)
+		}
+		line := fmt.Sprintf("%s%s %8d %7.1f%% %10s  %-10s %s", cursor,
+			padCell(r.prefix+marker+r.info.Name, nameWidth, false), r.info.PID,
+			r.info.CPUPercent, formatMB(r.info.RSSMB), r.info.State, note)
+		if i == pos {
+			s += selected.Render(line) + "\n"
+		} else {
+			s += unselected.Render(line) + "\n"
+		}
+	}
+
+	info := fmt.Sprintf("%d processes", len(rows))
+	if len(rows) > end-start {
+		info = fmt.Sprintf(glyphs("%d–%d of %d processes"), start+1, end, len(rows))
+	}
+	s += faint.Render("  "+info) + "\n"
+
+	if pos >= 0 {
+		s += "\n" + headerStyle.Render("Path ") + base.Render(orDash(rows[pos].info.Exe)) + "\n"
+	} else {
+		s += "\n\n"
+	}
+
+	switch {
+	case m.explorer.searching:
+		s += m.explorer.search.View() + "\n"
+	case m.explorer.confirm != "" && pos >= 0:
+		p := rows[pos].info
+		s += warnStyle.Render(fmt.Sprintf(glyphs("%s %s (PID %d)? Enter: confirm • Esc: cancel"),
+			strings.ToUpper(m.explorer.confirm), p.Name, p.PID)) + "\n"
+	case m.explorer.message != "":
+		s += base.Render(m.explorer.message) + "\n"
+	case m.explorer.search.Value() != "":
+		s += faint.Render(fmt.Sprintf("Search: %s (esc: clear)", m.explorer.search.Value())) + "\n"
+	default:
+		s += "\n"
+	}
+
+	s += "\n" + faint.Render(fmt.Sprintf(glyphs("←/→/%s: collapse/expand • %s: sort • %s: reverse • %s: search • %s: kill • %s: suspend • %s: resume • %s: add to apps • esc: back"),
+		hint(m.keys.Toggle), hint(m.keys.Sort), hint(m.keys.SortReverse), hint(m.keys.Filter),
+		hint(m.keys.Kill), hint(m.keys.Suspend), hint(m.keys.Resume), hint(m.keys.NewItem))) + "\n"
+	return s
+}
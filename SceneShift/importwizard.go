@@ -0,0 +1,609 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Profile Import Wizard ---
+//
+// Importing a profile first compares it with the current config. Every app
+// and preset is listed as new, identical or conflicting, along with the
+// theme, exclusion list and safe-to-kill list, and each gets its own choice
+// of what to do before anything is written.
+
+type importKind int
+
+const (
+	importApp importKind = iota
+	importPreset
+	importTheme
+	importExclusions
+	importSafeToKill
+)
+
+type importAction int
+
+const (
+	importSkip    importAction = iota
+	importAdd                  // Add an entry that does not exist yet
+	importMerge                // Keep the current entry, filling in from the profile
+	importReplace              // Overwrite the current entry with the profile's
+)
+
+func (a importAction) String() string {
+	switch a {
+	case importAdd:
+		return "add"
+	case importMerge:
+		return "merge"
+	case importReplace:
+		return "replace"
+	}
+	return "skip"
+}
+
+const (
+	importNew       = "new"
+	importIdentical = "identical"
+	importConflict  = "conflict"
+)
+
+// importItem is one entry of a profile and what to do with it
+type importItem struct {
+	kind    importKind
+	name    string
+	status  string
+	detail  string // What differs from the current config
+	choices []importAction
+	action  importAction
+
+	keyOwner string // Current preset holding a new preset's key
+	keyBound bool   // A new preset's key is bound to an action
+
+	app    AppEntry
+	preset PresetConfig
+	theme  ThemeConfig
+	names  []string // Exclusion or safe-to-kill entries
+}
+
+// loadProfile reads a profile file, upgrading older formats. It returns the
+// format the file was written in.
+func loadProfile(path string) (ConfigProfile, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ConfigProfile{}, "", fmt.Errorf("failed to read file: %v", err)
+	}
+	return decodeProfile(data)
+}
+
+// planImport compares a profile with the current config
+func (m model) planImport(profile ConfigProfile) []importItem {
+	var items []importItem
+
+	for _, app := range profile.Apps {
+		item := importItem{kind: importApp, name: app.Name, app: app}
+		if i := findApp(m.config.Apps, app.Name); i < 0 {
+			item.status = importNew
+		} else if diff := appDiff(m.config.Apps[i], app); len(diff) == 0 {
+			item.status = importIdentical
+		} else {
+			item.status = importConflict
+			item.detail = strings.Join(diff, ", ") + " differ"
+		}
+		items = append(items, item)
+	}
+
+	for _, p := range profile.Presets {
+		items = append(items, m.planPreset(p, profile.Presets))
+	}
+
+	item := importItem{kind: importTheme, name: "Theme", theme: profile.Theme}
+	switch {
+	case profile.Theme == (ThemeConfig{}) || profile.Theme == m.config.Theme:
+		item.status = importIdentical
+	default:
+		item.status = importConflict
+		item.detail = "colors differ"
+		if profile.Theme.Name != "" {
+			item.detail = profile.Theme.Name + " theme"
+		}
+	}
+	items = append(items, item)
+
+	items = append(items, planList(importExclusions, "Exclusion list", m.config.Protection.ExclusionList, profile.Protection.ExclusionList))
+	items = append(items, planList(importSafeToKill, "Safe-to-kill list", m.config.SafeToKill.all(), profile.SafeToKill.all()))
+
+	for i := range items {
+		items[i].choices, items[i].action = importChoices(items[i])
+	}
+	return items
+}
+
+// planPreset checks a preset against the current ones by name and by key
+func (m model) planPreset(p PresetConfig, incoming []PresetConfig) importItem {
+	item := importItem{kind: importPreset, name: p.Name, preset: p}
+
+	if i := findPreset(m.config.Presets, p.Name); i >= 0 {
+		current := m.config.Presets[i]
+		var diff []string
+		if current.Key != p.Key {
+			diff = append(diff, fmt.Sprintf(glyphs("key %q → %q"), current.Key, p.Key))
+		}
+		if !sameNames(current.Apps, p.Apps) {
+			diff = append(diff, "apps")
+		}
+		if len(diff) == 0 {
+			item.status = importIdentical
+			return item
+		}
+		item.status = importConflict
+		item.detail = strings.Join(diff, ", ") + " differ"
+		return item
+	}
+
+	item.status = importNew
+	if p.Key == "" {
+		return item
+	}
+	if owner := m.keys.boundTo(p.Key); owner != "" {
+		item.status = importConflict
+		item.keyBound = true
+		item.detail = fmt.Sprintf("key %q is bound to %s; added without a key", p.Key, owner)
+		return item
+	}
+	for _, other := range m.config.Presets {
+		if other.Key == p.Key && findPreset(incoming, other.Name) < 0 {
+			item.status = importConflict
+			item.keyOwner = other.Name
+			item.detail = fmt.Sprintf("key %q is used by %s", p.Key, other.Name)
+		}
+	}
+	return item
+}
+
+// planList compares an exclusion or safe-to-kill list
+func planList(kind importKind, name string, current, incoming []string) importItem {
+	item := importItem{kind: kind, name: name, names: incoming}
+	added := 0
+	for _, n := range incoming {
+		if !containsFold(current, n) {
+			added++
+		}
+	}
+	removed := 0
+	for _, n := range current {
+		if !containsFold(incoming, n) {
+			removed++
+		}
+	}
+
+	switch {
+	case len(incoming) == 0 || (added == 0 && removed == 0):
+		item.status = importIdentical
+	case added == 0:
+		item.status = importIdentical
+		item.detail = fmt.Sprintf("%d entries not in the profile", removed)
+	default:
+		item.status = importConflict
+		item.detail = fmt.Sprintf("+%d new, %d not in the profile", added, removed)
+		if removed == 0 {
+			item.status = importNew
+			item.detail = fmt.Sprintf("+%d new", added)
+		}
+	}
+	return item
+}
+
+// importChoices returns the actions offered for an item, default first
+func importChoices(item importItem) ([]importAction, importAction) {
+	switch {
+	case item.kind == importExclusions || item.kind == importSafeToKill:
+		if item.status == importIdentical && item.detail == "" {
+			return nil, importSkip
+		}
+		// Replacing can drop entries; merging only adds
+		if item.status == importIdentical {
+			return []importAction{importSkip, importReplace}, importSkip
+		}
+		return []importAction{importMerge, importReplace, importSkip}, importMerge
+	case item.status == importIdentical:
+		return nil, importSkip
+	case item.kind == importTheme:
+		return []importAction{importSkip, importReplace}, importSkip
+	case item.status == importNew:
+		return []importAction{importAdd, importSkip}, importAdd
+	case item.keyOwner != "":
+		// Add without the key, or take it over from the current preset
+		return []importAction{importAdd, importReplace, importSkip}, importAdd
+	case item.keyBound:
+		// It can only be added without its key
+		return []importAction{importAdd, importSkip}, importAdd
+	}
+	return []importAction{importSkip, importMerge, importReplace}, importSkip
+}
+
+// applyImport carries out the chosen actions and saves
+func (m *model) applyImport() {
+	counts := map[importAction]int{}
+	for _, item := range m.importItems {
+		if item.action == importSkip {
+			if item.status != importIdentical || item.detail != "" {
+				counts[importSkip]++
+			}
+			continue
+		}
+		counts[item.action]++
+
+		switch item.kind {
+		case importApp:
+			m.importApp(item)
+		case importPreset:
+			m.importPreset(item)
+		case importTheme:
+			m.config.Theme = item.theme
+		case importExclusions:
+			if item.action == importReplace {
+				m.config.Protection.ExclusionList = slices.Clone(item.names)
+			} else {
+				m.config.Protection.ExclusionList = appendUnique(m.config.Protection.ExclusionList, item.names)
+			}
+		case importSafeToKill:
+			if item.action == importReplace {
+				m.config.SafeToKill = m.importing.SafeToKill
+			} else {
+				m.config.SafeToKill = mergeSafeToKill(m.config.SafeToKill, m.importing.SafeToKill)
+			}
+		}
+	}
+
+	removed := 0
+	if m.importRemoveMissing {
+		removed = m.removeNotInProfile()
+	}
+
+	m.saveConfig()
+	m.profileMessage = fmt.Sprintf(glyphs("✅ Imported %s: %d added, %d merged, %d replaced, %d skipped"),
+		m.importFile, counts[importAdd], counts[importMerge], counts[importReplace], counts[importSkip])
+	if removed > 0 {
+		m.profileMessage += fmt.Sprintf(", %d removed", removed)
+	}
+}
+
+func (m *model) importApp(item importItem) {
+	app := item.app
+	app.PIDs = nil
+	app.Sources = nil
+	i := findApp(m.config.Apps, app.Name)
+	switch {
+	case i < 0:
+		app.SafetyLevel = detectSafetyLevel(app.ProcessName, &m.config)
+		m.config.Apps = append(m.config.Apps, app)
+	case item.action == importReplace:
+		current := m.config.Apps[i]
+		app.Selected = current.Selected
+		app.PIDs = current.PIDs
+		app.Sources = current.Sources
+		m.config.Apps[i] = app
+	case item.action == importMerge:
+		current := &m.config.Apps[i]
+		if current.ProcessName == "" {
+			current.ProcessName = app.ProcessName
+		}
+		if current.ExecPath == "" {
+			current.ExecPath = app.ExecPath
+		}
+		if len(current.Args) == 0 {
+			current.Args = app.Args
+		}
+	}
+}
+
+func (m *model) importPreset(item importItem) {
+	p := item.preset
+	p.Source = configLayer{}
+	i := findPreset(m.config.Presets, p.Name)
+	switch {
+	case i >= 0 && item.action == importReplace:
+		current := m.config.Presets[i]
+		p.Source = current.Source
+		if p.Key != current.Key && !m.presetKeyFree(p.Key, i) {
+			p.Key = current.Key
+		}
+		m.config.Presets[i] = p
+	case i >= 0:
+		// Merge: keep the current key, add the profile's apps
+		m.config.Presets[i].Apps = appendUnique(m.config.Presets[i].Apps, p.Apps)
+	default:
+		if p.Key != "" && m.keys.boundTo(p.Key) != "" {
+			p.Key = ""
+		}
+		for j := range m.config.Presets {
+			if p.Key != "" && m.config.Presets[j].Key == p.Key {
+				if item.action == importReplace {
+					// Take the key over from the current preset
+					m.config.Presets[j].Key = ""
+				} else {
+					p.Key = ""
+				}
+			}
+		}
+		m.config.Presets = append(m.config.Presets, p)
+	}
+}
+
+// presetKeyFree reports whether a key is unused by actions and by presets
+// other than the one at index skip
+func (m *model) presetKeyFree(k string, skip int) bool {
+	if k == "" {
+		return true
+	}
+	if m.keys.boundTo(k) != "" {
+		return false
+	}
+	for j, p := range m.config.Presets {
+		if j != skip && p.Key == k {
+			return false
+		}
+	}
+	return true
+}
+
+// removeNotInProfile drops apps and presets the profile does not have,
+// turning the import into a replacement of the whole config
+func (m *model) removeNotInProfile() int {
+	removed := 0
+	apps := m.config.Apps[:0]
+	for _, app := range m.config.Apps {
+		if findApp(m.importing.Apps, app.Name) >= 0 {
+			apps = append(apps, app)
+		} else {
+			removed++
+		}
+	}
+	m.config.Apps = apps
+
+	presets := m.config.Presets[:0]
+	for _, p := range m.config.Presets {
+		if findPreset(m.importing.Presets, p.Name) >= 0 {
+			presets = append(presets, p)
+		} else {
+			removed++
+		}
+	}
+	m.config.Presets = presets
+
+	if m.cursor >= len(m.config.Apps) {
+		m.cursor = max(len(m.config.Apps)-1, 0)
+	}
+	if m.presetCursor >= len(m.config.Presets) {
+		m.presetCursor = max(len(m.config.Presets)-1, 0)
+	}
+	return removed
+}
+
+// --- Comparison Helpers ---
+
+// appDiff lists the fields that differ between two entries for one app
+func appDiff(current, incoming AppEntry) []string {
+	var diff []string
+	if !strings.EqualFold(current.ProcessName, incoming.ProcessName) {
+		diff = append(diff, "process_name")
+	}
+	if !strings.EqualFold(current.ExecPath, incoming.ExecPath) {
+		diff = append(diff, "exec_path")
+	}
+	if !slices.Equal(current.Args, incoming.Args) {
+		diff = append(diff, "args")
+	}
+	return diff
+}
+
+func findPreset(presets []PresetConfig, name string) int {
+	for i := range presets {
+		if strings.EqualFold(presets[i].Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// sameNames compares two lists of names ignoring order and case
+func sameNames(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, n := range a {
+		if !containsFold(b, n) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsFold(list []string, name string) bool {
+	for _, n := range list {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// all returns every entry of the safe-to-kill list
+func (s SafeToKillConfig) all() []string {
+	var all []string
+	for _, list := range [][]string{s.Bloatware, s.ChatApps, s.GameLaunchers, s.Utilities} {
+		all = append(all, list...)
+	}
+	return all
+}
+
+func mergeSafeToKill(a, b SafeToKillConfig) SafeToKillConfig {
+	return SafeToKillConfig{
+		Bloatware:     appendUnique(a.Bloatware, b.Bloatware),
+		ChatApps:      appendUnique(a.ChatApps, b.ChatApps),
+		GameLaunchers: appendUnique(a.GameLaunchers, b.GameLaunchers),
+		Utilities:     appendUnique(a.Utilities, b.Utilities),
+	}
+}
+
+// --- Wizard Screen ---
+
+// startImportReview loads the chosen profile and shows what it would change
+func (m model) startImportReview(filename string) (tea.Model, tea.Cmd) {
+	profile, format, err := loadProfile(filepath.Join(configDir(), filename))
+	if err != nil {
+		m.profileMessage = fmt.Sprintf(glyphs("❌ Import failed: %v"), err)
+		m.currentState = stateMenu
+		return m, nil
+	}
+
+	m.importing = profile
+	m.importFile = filename
+	m.importFormat = format
+	m.importItems = m.planImport(profile)
+	m.importCursor = 0
+	m.importRemoveMissing = false
+	m.currentState = stateImportReview
+	return m, nil
+}
+
+func (m model) updateImportReview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.importCursor > 0 {
+			m.importCursor--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.importCursor < len(m.importItems)-1 {
+			m.importCursor++
+		}
+	case key.Matches(msg, m.keys.Toggle), msg.String() == "right", msg.String() == "left":
+		item := &m.importItems[m.importCursor]
+		if len(item.choices) > 1 {
+			i := slices.Index(item.choices, item.action)
+			step := 1
+			if msg.String() == "left" {
+				step = len(item.choices) - 1
+			}
+			item.action = item.choices[(i+step)%len(item.choices)]
+		}
+	case msg.String() == "m", msg.String() == "r", msg.String() == "s":
+		// Apply the same choice to every item that offers it
+		want := map[string]importAction{"m": importMerge, "r": importReplace, "s": importSkip}[msg.String()]
+		for i := range m.importItems {
+			item := &m.importItems[i]
+			if slices.Contains(item.choices, want) {
+				item.action = want
+			} else if want == importMerge && slices.Contains(item.choices, importAdd) {
+				item.action = importAdd
+			}
+		}
+	case msg.String() == "ctrl+r":
+		m.importRemoveMissing = !m.importRemoveMissing
+	case msg.String() == "enter":
+		m.applyImport()
+		m.currentState = stateMenu
+	case msg.String() == "esc":
+		m.currentState = stateProfileImport
+	}
+	return m, nil
+}
+
+func (m model) viewImportReview() string {
+	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Select)).Bold(true).Underline(true)
+	base := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Text))
+	selected := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Select)).Bold(true)
+	unselected := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Text)).Faint(true)
+	warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Warn))
+	killStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Kill)).Bold(true)
+	newStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Restore))
+
+	var s string
+	s += titleStyle.Render(glyphs("📥 IMPORT ")+m.importFile) + "\n\n"
+	meta := m.importing.Metadata
+	about := meta.Description
+	if meta.Author != "" {
+		about += " by " + meta.Author
+	}
+	if !meta.ExportDate.IsZero() {
+		about += glyphs(" • exported ") + meta.ExportDate.Format("2006-01-02")
+	}
+	s += base.Render(about) + "\n"
+	if m.importFormat != meta.Version {
+		s += unselected.Render(fmt.Sprintf("Upgraded from profile format %s", m.importFormat)) + "\n"
+	}
+	if newerThanRunning(meta.SceneShiftVersion) {
+		s += warnStyle.Render(fmt.Sprintf(glyphs("⚠️ Exported by a newer SceneShift (v%s); settings it added are ignored"), meta.SceneShiftVersion)) + "\n"
+	}
+	s += "\n"
+
+	// Keep the cursor in view on short terminals
+	rows := max(m.height-14, 5)
+	start := 0
+	if m.importCursor >= rows {
+		start = m.importCursor - rows + 1
+	}
+	end := min(start+rows, len(m.importItems))
+
+	headings := map[importKind]string{importApp: "APPS", importPreset: "PRESETS", importTheme: "SETTINGS"}
+	for i := start; i < end; i++ {
+		item := m.importItems[i]
+		if h, ok := headings[item.kind]; ok && (i == start || m.importItems[i-1].kind != item.kind) {
+			s += unselected.Render(h) + "\n"
+		}
+
+		marker, markerStyle := "=", unselected
+		switch item.status {
+		case importNew:
+			marker, markerStyle = "+", newStyle
+		case importConflict:
+			marker, markerStyle = "!", warnStyle
+		}
+		status := item.status
+		if item.detail != "" {
+			status += ": " + item.detail
+		}
+		action := ""
+		if len(item.choices) > 0 {
+			action = "[" + item.action.String() + "]"
+		}
+
+		line := fmt.Sprintf("%-24s %-10s %s", truncate(item.name, 24), action, status)
+		if i == m.importCursor {
+			s += selected.Render("> ") + markerStyle.Render(marker) + " " + selected.Render(line) + "\n"
+		} else {
+			s += "  " + markerStyle.Render(marker) + " " + unselected.Render(line) + "\n"
+		}
+	}
+
+	s += "\n"
+	if m.importRemoveMissing {
+		s += killStyle.Render("[x] Remove apps and presets not in the profile") + "\n"
+	} else {
+		s += base.Render("[ ] Remove apps and presets not in the profile") + "\n"
+	}
+	s += "\n" + lipgloss.NewStyle().Faint(true).Render(hint(m.keys.Toggle)+glyphs("/←/→: Change choice • m/r/s: Merge/replace/skip all • Ctrl+R: Replace whole config • Enter: Import • Esc: Back")) + "\n"
+	return s
+}
+
+// truncate shortens s to n runes, marking the cut with an ellipsis
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	e := ellipsis()
+	if n <= len(e) {
+		return string(r[:n])
+	}
+	return string(r[:n-len(e)]) + e
+}
@@ -0,0 +1,666 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// --- Config Includes and Overlays ---
+//
+// config.yaml may include other YAML files and carry overlays that only
+// apply on some machines:
+//
+//	include: [team.yaml]
+//	overlays:
+//	  LAB-07:           # hostname
+//	    apps: [...]
+//	  windows:          # or OS (runtime.GOOS)
+//	    apps: [...]
+//
+// Settings are layered: a file's includes first (in order), then the file
+// itself, then its OS overlay, then its host overlay. Later layers win:
+//
+//   - apps are matched by name; each non-empty field replaces the earlier value
+//   - presets and packs are matched by name and replaced as a whole
+//   - exclusion and safe-to-kill lists are combined
+//   - hotkeys are replaced per action and display per mode; audit,
+//     backup_count, refresh_interval and disable_mouse when set
+//
+// Each value remembers the layer it came from, and edits are saved back to
+// that file. Anything new goes into config.yaml.
+
+// ConfigOverlay holds the settings an overlay may change
+type ConfigOverlay struct {
+	Apps       []AppEntry       `yaml:"apps,omitempty"`
+	Presets    []PresetConfig   `yaml:"presets,omitempty"`
+	Protection ProtectionConfig `yaml:"protection,omitempty"`
+	SafeToKill SafeToKillConfig `yaml:"safe_to_kill,omitempty"`
+}
+
+// configLayer identifies where a value was defined: a file, or an overlay
+// within it
+type configLayer struct {
+	file    string
+	overlay string // "" for the file's top level
+}
+
+func (l configLayer) String() string {
+	if l.overlay == "" {
+		return filepath.Base(l.file)
+	}
+	return fmt.Sprintf("%s (overlay %s)", filepath.Base(l.file), l.overlay)
+}
+
+// configSources records the files a layered config was built from
+type configSources struct {
+	files     []string                 // Included files, then config.yaml
+	docs      map[string]*Config       // Contents of each file as last read or written
+	layers    []configLayer            // In the order they were applied
+	exclusion map[string][]configLayer // Every layer listing an exclusion
+}
+
+// hasLayers reports whether a config uses includes or overlays
+func (cfg Config) hasLayers() bool {
+	return len(cfg.Include) > 0 || len(cfg.Overlays) > 0
+}
+
+// overlayKeys returns the overlay names that apply to this machine, in the
+// order they are applied
+func overlayKeys(overlays map[string]ConfigOverlay) []string {
+	host, _ := os.Hostname()
+	var keys []string
+	for k := range overlays {
+		if strings.EqualFold(k, runtime.GOOS) {
+			keys = append(keys, k)
+		}
+	}
+	for k := range overlays {
+		if host != "" && strings.EqualFold(k, host) && !strings.EqualFold(k, runtime.GOOS) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// resolveLayers merges the includes and matching overlays of config.yaml
+// into a single Config. main is the parsed config.yaml.
+func resolveLayers(main Config) (Config, error) {
+	src := &configSources{
+		docs:      map[string]*Config{},
+		exclusion: map[string][]configLayer{},
+	}
+	mainDoc := main
+	if err := src.collect(configFile, &mainDoc, map[string]bool{}); err != nil {
+		return Config{}, err
+	}
+
+	merged := Config{
+		SchemaVersion: main.SchemaVersion,
+		Include:       main.Include,
+		Overlays:      main.Overlays,
+		Sources:       src,
+	}
+	for _, layer := range src.layers {
+		doc := src.docs[layer.file]
+		if layer.overlay == "" {
+			mergeSettings(&merged, *doc)
+			mergeLayer(&merged, src, layer, doc.Apps, doc.Presets, doc.Protection, doc.SafeToKill)
+			for _, p := range doc.Packs {
+				p.Source = layer
+				merged.Packs = upsertPack(merged.Packs, p)
+			}
+		} else {
+			o := doc.Overlays[layer.overlay]
+			mergeLayer(&merged, src, layer, o.Apps, o.Presets, o.Protection, o.SafeToKill)
+		}
+	}
+	return merged, nil
+}
+
+// collect reads a file's includes depth-first and records its layers
+func (src *configSources) collect(path string, doc *Config, visiting map[string]bool) error {
+	if visiting[path] {
+		return fmt.Errorf("include cycle at %s", path)
+	}
+	visiting[path] = true
+	defer delete(visiting, path)
+
+	for _, inc := range doc.Include {
+		incPath := inc
+		if !filepath.IsAbs(incPath) {
+			incPath = filepath.Join(filepath.Dir(path), incPath)
+		}
+		if _, seen := src.docs[incPath]; seen {
+			continue // Already applied through another include
+		}
+		data, err := os.ReadFile(incPath)
+		if err != nil {
+			return fmt.Errorf("could not read include %s: %w", inc, err)
+		}
+		var incDoc Config
+		if err := yaml.Unmarshal(data, &incDoc); err != nil {
+			return fmt.Errorf("could not parse include %s: %w", inc, err)
+		}
+		if err := src.collect(incPath, &incDoc, visiting); err != nil {
+			return err
+		}
+	}
+
+	src.files = append(src.files, path)
+	src.docs[path] = doc
+	src.layers = append(src.layers, configLayer{file: path})
+	for _, key := range overlayKeys(doc.Overlays) {
+		src.layers = append(src.layers, configLayer{file: path, overlay: key})
+	}
+	return nil
+}
+
+// mergeSettings applies the non-list settings of a file
+func mergeSettings(dst *Config, doc Config) {
+	dst.Hotkeys = mergeHotkeys(dst.Hotkeys, doc.Hotkeys)
+	if doc.BackupCount != 0 {
+		dst.BackupCount = doc.BackupCount
+	}
+	if doc.RefreshInterval != 0 {
+		dst.RefreshInterval = doc.RefreshInterval
+	}
+	if doc.DisableMouse {
+		dst.DisableMouse = true
+	}
+	if doc.Display.ASCII != nil {
+		dst.Display.ASCII = doc.Display.ASCII
+	}
+	if doc.Display.HighContrast != nil {
+		dst.Display.HighContrast = doc.Display.HighContrast
+	}
+	if doc.Display.ScreenReader != nil {
+		dst.Display.ScreenReader = doc.Display.ScreenReader
+	}
+	if doc.Audit.Path != "" {
+		dst.Audit.Path = doc.Audit.Path
+	}
+	if doc.Audit.MaxSizeMB != 0 {
+		dst.Audit.MaxSizeMB = doc.Audit.MaxSizeMB
+	}
+	if doc.Audit.MaxBackups != 0 {
+		dst.Audit.MaxBackups = doc.Audit.MaxBackups
+	}
+	for name, value := range doc.Variables {
+		if dst.Variables == nil {
+			dst.Variables = map[string]string{}
+		}
+		dst.Variables[name] = value
+	}
+}
+
+// mergeHotkeys replaces each action of base that is set in over
+func mergeHotkeys(base, over HotkeyConfig) HotkeyConfig {
+	pick := func(b, o []string) []string {
+		if len(o) > 0 {
+			return o
+		}
+		return b
+	}
+	return HotkeyConfig{
+		Up:            pick(base.Up, over.Up),
+		Down:          pick(base.Down, over.Down),
+		Toggle:        pick(base.Toggle, over.Toggle),
+		SelectAll:     pick(base.SelectAll, over.SelectAll),
+		DeselectAll:   pick(base.DeselectAll, over.DeselectAll),
+		KillMode:      pick(base.KillMode, over.KillMode),
+		SuspendMode:   pick(base.SuspendMode, over.SuspendMode),
+		ResumeMode:    pick(base.ResumeMode, over.ResumeMode),
+		RestoreMode:   pick(base.RestoreMode, over.RestoreMode),
+		Quit:          pick(base.Quit, over.Quit),
+		Help:          pick(base.Help, over.Help),
+		NewItem:       pick(base.NewItem, over.NewItem),
+		EditItem:      pick(base.EditItem, over.EditItem),
+		DeleteItem:    pick(base.DeleteItem, over.DeleteItem),
+		SearchProcess: pick(base.SearchProcess, over.SearchProcess),
+		ThemeMenu:     pick(base.ThemeMenu, over.ThemeMenu),
+		PresetMenu:    pick(base.PresetMenu, over.PresetMenu),
+		SafelistMenu:  pick(base.SafelistMenu, over.SafelistMenu),
+		History:       pick(base.History, over.History),
+		Undo:          pick(base.Undo, over.Undo),
+		Export:        pick(base.Export, over.Export),
+		Import:        pick(base.Import, over.Import),
+		FindExec:      pick(base.FindExec, over.FindExec),
+		RepairPaths:   pick(base.RepairPaths, over.RepairPaths),
+		Details:       pick(base.Details, over.Details),
+		Sort:          pick(base.Sort, over.Sort),
+		SortReverse:   pick(base.SortReverse, over.SortReverse),
+		Filter:        pick(base.Filter, over.Filter),
+		Explorer:      pick(base.Explorer, over.Explorer),
+		Palette:       pick(base.Palette, over.Palette),
+		LogProblems:   pick(base.LogProblems, over.LogProblems),
+		SaveLog:       pick(base.SaveLog, over.SaveLog),
+	}
+}
+
+// mergeLayer applies the lists of one layer
+func mergeLayer(dst *Config, src *configSources, layer configLayer, apps []AppEntry, presets []PresetConfig, protection ProtectionConfig, safe SafeToKillConfig) {
+	for _, app := range apps {
+		i := findApp(dst.Apps, app.Name)
+		if i < 0 {
+			dst.Apps = append(dst.Apps, AppEntry{Name: app.Name, Sources: map[string]configLayer{"name": layer}})
+			i = len(dst.Apps) - 1
+		}
+		mergeApp(&dst.Apps[i], app, layer)
+	}
+
+	for _, p := range presets {
+		p.Source = layer
+		replaced := false
+		for i := range dst.Presets {
+			if strings.EqualFold(dst.Presets[i].Name, p.Name) {
+				dst.Presets[i] = p
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			dst.Presets = append(dst.Presets, p)
+		}
+	}
+
+	for _, name := range protection.ExclusionList {
+		if _, ok := src.exclusion[strings.ToLower(name)]; !ok {
+			dst.Protection.ExclusionList = append(dst.Protection.ExclusionList, name)
+		}
+		src.exclusion[strings.ToLower(name)] = append(src.exclusion[strings.ToLower(name)], layer)
+	}
+
+	dst.SafeToKill.Bloatware = appendUnique(dst.SafeToKill.Bloatware, safe.Bloatware)
+	dst.SafeToKill.ChatApps = appendUnique(dst.SafeToKill.ChatApps, safe.ChatApps)
+	dst.SafeToKill.GameLaunchers = appendUnique(dst.SafeToKill.GameLaunchers, safe.GameLaunchers)
+	dst.SafeToKill.Utilities = appendUnique(dst.SafeToKill.Utilities, safe.Utilities)
+}
+
+// mergeApp copies the fields set in src, recording which layer set them
+func mergeApp(dst *AppEntry, src AppEntry, layer configLayer) {
+	if src.ProcessName != "" {
+		dst.ProcessName = src.ProcessName
+		dst.Sources["process_name"] = layer
+	}
+	if src.ExecPath != "" {
+		dst.ExecPath = src.ExecPath
+		dst.Sources["exec_path"] = layer
+	}
+	if len(src.Args) > 0 {
+		dst.Args = src.Args
+		dst.Sources["args"] = layer
+	}
+	if src.SafetyLevel != "" {
+		dst.SafetyLevel = src.SafetyLevel
+		dst.Sources["safety_level"] = layer
+	}
+	if src.Selected {
+		dst.Selected = true
+	}
+}
+
+func findApp(apps []AppEntry, name string) int {
+	for i := range apps {
+		if strings.EqualFold(apps[i].Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+func upsertPack(packs []PackRef, p PackRef) []PackRef {
+	for i := range packs {
+		if strings.EqualFold(packs[i].Name, p.Name) {
+			packs[i] = p
+			return packs
+		}
+	}
+	return append(packs, p)
+}
+
+func appendUnique(list, extra []string) []string {
+	for _, e := range extra {
+		found := false
+		for _, l := range list {
+			if strings.EqualFold(l, e) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, e)
+		}
+	}
+	return list
+}
+
+// --- Saving Layered Configs ---
+
+// layerContent collects what will be written to one layer
+type layerContent struct {
+	apps       []AppEntry
+	presets    []PresetConfig
+	exclusions []string
+	packs      []PackRef
+}
+
+// writeLayeredConfig saves each app, preset, exclusion and pack back to
+// the layer it came from. Files whose content is unchanged are not touched.
+func writeLayeredConfig(cfg Config) error {
+	src := cfg.Sources
+	mainLayer := configLayer{file: configFile}
+	content := map[configLayer]*layerContent{}
+	for _, layer := range src.layers {
+		content[layer] = &layerContent{}
+	}
+	known := func(layer configLayer, ok bool) configLayer {
+		if _, found := content[layer]; ok && found {
+			return layer
+		}
+		return mainLayer
+	}
+
+	for _, app := range cfg.Apps {
+		nameLayer, ok := app.Sources["name"]
+		base := known(nameLayer, ok)
+
+		for _, layer := range src.layers {
+			entry, had := src.originalApp(layer, app.Name)
+			owns := layer == base
+			for _, l := range app.Sources {
+				owns = owns || l == layer
+			}
+			if !had && !owns {
+				continue
+			}
+
+			// Start from what the layer had, so values hidden by a later
+			// layer are kept
+			entry.Name = app.Name
+			entry.PIDs = nil
+			entry.Sources = nil
+			if layer == base {
+				entry.Selected = app.Selected
+			}
+			for field, value := range map[string]*string{
+				"process_name": &entry.ProcessName,
+				"exec_path":    &entry.ExecPath,
+				"safety_level": &entry.SafetyLevel,
+			} {
+				from, ok := app.Sources[field]
+				if !ok && field == "safety_level" && (had || base != mainLayer) {
+					continue // Detected on load; kept out of the file as it was loaded
+				}
+				if (ok && known(from, true) == layer) || (!ok && layer == base) {
+					*value = app.field(field)
+				}
+			}
+			if from, ok := app.Sources["args"]; (ok && known(from, true) == layer) || (!ok && layer == base) {
+				entry.Args = app.Args
+			}
+			content[layer].apps = append(content[layer].apps, entry)
+		}
+	}
+
+	// A preset or pack replaced by a later layer stays as it was in the
+	// earlier ones
+	for _, p := range cfg.Presets {
+		from := known(p.Source, p.Source.file != "")
+		p.Source = configLayer{}
+		for _, layer := range src.layers {
+			if layer == from {
+				content[layer].presets = append(content[layer].presets, p)
+			} else if orig, ok := src.originalPreset(layer, p.Name); ok {
+				content[layer].presets = append(content[layer].presets, orig)
+			}
+		}
+	}
+	for _, p := range cfg.Packs {
+		from := known(p.Source, p.Source.file != "")
+		for _, layer := range src.layers {
+			if layer == from {
+				content[layer].packs = append(content[layer].packs, p)
+			} else if orig, ok := src.originalPack(layer, p.Name); ok {
+				content[layer].packs = append(content[layer].packs, orig)
+			}
+		}
+	}
+	for _, name := range cfg.Protection.ExclusionList {
+		layers := src.exclusion[strings.ToLower(name)]
+		if len(layers) == 0 {
+			layers = []configLayer{mainLayer}
+		}
+		for _, layer := range layers {
+			c := content[known(layer, true)]
+			c.exclusions = append(c.exclusions, name)
+		}
+	}
+
+	for _, file := range src.files {
+		doc := *src.docs[file]
+		base := content[configLayer{file: file}]
+		doc.Apps = base.apps
+		doc.Presets = base.presets
+		doc.Protection.ExclusionList = base.exclusions
+		doc.Packs = base.packs
+
+		if len(doc.Overlays) > 0 {
+			overlays := make(map[string]ConfigOverlay, len(doc.Overlays))
+			for key, o := range doc.Overlays {
+				if c, applied := content[configLayer{file: file, overlay: key}]; applied {
+					o.Apps = c.apps
+					o.Presets = c.presets
+					o.Protection.ExclusionList = c.exclusions
+				}
+				overlays[key] = o
+			}
+			doc.Overlays = overlays
+		}
+
+		before, _ := encodeYAML(*src.docs[file])
+		after, err := encodeYAML(doc)
+		if err != nil {
+			return fmt.Errorf("could not encode %s: %w", filepath.Base(file), err)
+		}
+		if bytes.Equal(before, after) {
+			continue
+		}
+
+		// Only rewrite the lists that changed, keeping comments, layout and
+		// everything else in the file
+		data, err := patchLayerFile(file, doc, content)
+		if err != nil {
+			return err
+		}
+		if file == configFile {
+			err = writeConfigData(data, cfg.BackupCount)
+		} else if err = writeFileAtomic(file, data); err != nil {
+			err = fmt.Errorf("could not write %s: %w", file, err)
+		}
+		if err != nil {
+			return err
+		}
+		src.docs[file] = &doc
+	}
+
+	// Entries added since loading now belong to the layer they were saved to
+	for _, name := range cfg.Protection.ExclusionList {
+		if _, ok := src.exclusion[strings.ToLower(name)]; !ok {
+			src.exclusion[strings.ToLower(name)] = []configLayer{mainLayer}
+		}
+	}
+	return nil
+}
+
+// originalApp returns a layer's entry for an app as it was last read or
+// written
+func (src *configSources) originalApp(layer configLayer, name string) (AppEntry, bool) {
+	doc := src.docs[layer.file]
+	apps := doc.Apps
+	if layer.overlay != "" {
+		apps = doc.Overlays[layer.overlay].Apps
+	}
+	if i := findApp(apps, name); i >= 0 {
+		return apps[i], true
+	}
+	return AppEntry{}, false
+}
+
+// originalPreset returns a layer's definition of a preset as it was last
+// read or written
+func (src *configSources) originalPreset(layer configLayer, name string) (PresetConfig, bool) {
+	doc := src.docs[layer.file]
+	presets := doc.Presets
+	if layer.overlay != "" {
+		presets = doc.Overlays[layer.overlay].Presets
+	}
+	for _, p := range presets {
+		if strings.EqualFold(p.Name, name) {
+			return p, true
+		}
+	}
+	return PresetConfig{}, false
+}
+
+// originalPack returns a file's pack entry as it was last read or written
+func (src *configSources) originalPack(layer configLayer, name string) (PackRef, bool) {
+	if layer.overlay != "" {
+		return PackRef{}, false
+	}
+	for _, p := range src.docs[layer.file].Packs {
+		if strings.EqualFold(p.Name, name) {
+			return p, true
+		}
+	}
+	return PackRef{}, false
+}
+
+// field returns an app field by its YAML name
+func (app AppEntry) field(name string) string {
+	switch name {
+	case "process_name":
+		return app.ProcessName
+	case "exec_path":
+		return app.ExecPath
+	case "safety_level":
+		return app.SafetyLevel
+	}
+	return ""
+}
+
+// patchLayerFile replaces the apps, presets, exclusion list and packs of a
+// file and its applied overlays, leaving everything else as is
+func patchLayerFile(file string, doc Config, content map[configLayer]*layerContent) ([]byte, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %w", file, err)
+	}
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", file, err)
+	}
+	if len(root.Content) == 0 {
+		root = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	top := root.Content[0]
+
+	patch := func(mapping *yaml.Node, c *layerContent, withPacks bool) error {
+		if err := setYAMLField(mapping, "apps", c.apps); err != nil {
+			return err
+		}
+		if err := setYAMLField(mapping, "presets", c.presets); err != nil {
+			return err
+		}
+		protection := childMapping(mapping, "protection", len(c.exclusions) > 0)
+		if protection != nil {
+			if err := setYAMLField(protection, "exclusion_list", c.exclusions); err != nil {
+				return err
+			}
+		}
+		if withPacks {
+			return setYAMLField(mapping, "packs", c.packs)
+		}
+		return nil
+	}
+
+	if err := patch(top, content[configLayer{file: file}], true); err != nil {
+		return nil, err
+	}
+	for key := range doc.Overlays {
+		c, applied := content[configLayer{file: file, overlay: key}]
+		if !applied {
+			continue
+		}
+		overlays := childMapping(top, "overlays", true)
+		if err := patch(childMapping(overlays, key, true), c, false); err != nil {
+			return nil, err
+		}
+	}
+
+	out, err := encodeYAML(&root)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode %s: %w", filepath.Base(file), err)
+	}
+	return out, nil
+}
+
+// childMapping returns the mapping stored under key, adding an empty one if
+// create is set
+func childMapping(mapping *yaml.Node, key string, create bool) *yaml.Node {
+	if _, value := mappingValue(mapping, key); value != nil {
+		return value
+	}
+	if !create {
+		return nil
+	}
+	value := &yaml.Node{Kind: yaml.MappingNode}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+	return value
+}
+
+// setYAMLField sets key to v, leaving the file alone when an absent key
+// would only be set to an empty list
+func setYAMLField[T any](mapping *yaml.Node, key string, v []T) error {
+	keyNode, _ := mappingValue(mapping, key)
+	if keyNode == nil && len(v) == 0 {
+		return nil
+	}
+	var value yaml.Node
+	if err := value.Encode(v); err != nil {
+		return err
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i] == keyNode {
+			value.HeadComment = mapping.Content[i+1].HeadComment
+			mapping.Content[i+1] = &value
+			return nil
+		}
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, &value)
+	return nil
+}
+
+// sourceFiles returns every file a config was read from
+func (cfg Config) sourceFiles() []string {
+	if cfg.Sources == nil {
+		return []string{configFile}
+	}
+	return cfg.Sources.files
+}
+
+// describeSource returns where an app field was defined, for display
+func (app AppEntry) describeSource(field string) string {
+	if layer, ok := app.Sources[field]; ok {
+		return layer.String()
+	}
+	if layer, ok := app.Sources["name"]; ok {
+		return layer.String()
+	}
+	return filepath.Base(configFile)
+}
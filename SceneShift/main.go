@@ -1,7 +1,6 @@
 package main
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -9,7 +8,6 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
-	"sync"
 	"syscall"
 	"time"
 
@@ -18,6 +16,7 @@ import (
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/shirou/gopsutil/v3/mem"
@@ -158,20 +157,19 @@ func getDefaultSafeToKill() SafeToKillConfig {
 func detectSafetyLevel(processName string, cfg *Config) string {
 	pLower := strings.ToLower(processName)
 
-	// Check if protected (in exclusion list)
+	// Check if protected (in exclusion list or locked by system policy)
 	for _, protected := range cfg.Protection.ExclusionList {
 		if strings.EqualFold(protected, processName) {
 			return "protected"
 		}
 	}
+	if cfg.Policy.Locks(processName) {
+		return "protected"
+	}
 
-	// Check if in safe-to-kill lists
-	allSafe := append(cfg.SafeToKill.Bloatware, cfg.SafeToKill.ChatApps...)
-	allSafe = append(allSafe, cfg.SafeToKill.GameLaunchers...)
-	allSafe = append(allSafe, cfg.SafeToKill.Utilities...)
-
-	for _, safe := range allSafe {
-		if strings.EqualFold(safe, processName) {
+	// Check if in safe-to-kill lists (config and enabled packs)
+	for _, safe := range cfg.safeToKillEntries() {
+		if strings.EqualFold(safe.Process, processName) {
 			return "safe"
 		}
 	}
@@ -187,36 +185,6 @@ func detectSafetyLevel(processName string, cfg *Config) string {
 	return "caution" // Default to caution for unknown processes
 }
 
-// migrateOldConfig handles backward compatibility from v2.1.0 to v2.1.1
-func migrateOldConfig(cfg *Config) bool {
-	migrated := false
-
-	// Migrate old "Safelist" to new "Protection.ExclusionList"
-	if len(cfg.Protection.ExclusionList) == 0 {
-		cfg.Protection.ExclusionList = getDefaultProtectionList()
-		migrated = true
-	}
-
-	// Add safe-to-kill lists if empty
-	if len(cfg.SafeToKill.Bloatware) == 0 &&
-		len(cfg.SafeToKill.ChatApps) == 0 &&
-		len(cfg.SafeToKill.GameLaunchers) == 0 &&
-		len(cfg.SafeToKill.Utilities) == 0 {
-		cfg.SafeToKill = getDefaultSafeToKill()
-		migrated = true
-	}
-
-	// Auto-detect safety levels for existing apps
-	for i := range cfg.Apps {
-		if cfg.Apps[i].SafetyLevel == "" {
-			cfg.Apps[i].SafetyLevel = detectSafetyLevel(cfg.Apps[i].ProcessName, cfg)
-			migrated = true
-		}
-	}
-
-	return migrated
-}
-
 // --- ASCII LOGO ---
 const logoASCII = `
    _____                     _____ __    _______
@@ -229,42 +197,64 @@ const logoASCII = `
 // --- Configuration ---
 
 type Config struct {
+	SchemaVersion int `yaml:"schema_version"`
+
+	Include  []string                 `yaml:"include,omitempty"`  // Files layered underneath this one
+	Overlays map[string]ConfigOverlay `yaml:"overlays,omitempty"` // Keyed by hostname or OS
+
 	Theme      ThemeConfig      `yaml:"-"`
 	Hotkeys    HotkeyConfig     `yaml:"hotkeys"`
 	Presets    []PresetConfig   `yaml:"presets"`
 	Apps       []AppEntry       `yaml:"apps"`
 	Protection ProtectionConfig `yaml:"protection"`
 	SafeToKill SafeToKillConfig `yaml:"safe_to_kill"`
+	Packs      []PackRef        `yaml:"packs,omitempty"`
+
+	BackupCount     int     `yaml:"backup_count,omitempty"`     // Config backups to keep (default 10)
+	RefreshInterval float64 `yaml:"refresh_interval,omitempty"` // Dashboard refresh in seconds (default 2)
+	DisableMouse    bool    `yaml:"disable_mouse,omitempty"`    // Leave the mouse to the terminal, e.g. for selecting text
+
+	Display DisplayConfig `yaml:"display,omitempty"` // ASCII, high contrast and screen reader modes
+
+	PackEntries []SafeToKillEntry `yaml:"-"`
+	Policy      Policy            `yaml:"-"`
+
+	Audit AuditConfig `yaml:"audit,omitempty"`
+
+	Variables map[string]string `yaml:"variables,omitempty"` // Custom ${NAME} values for paths
+
+	Sources *configSources `yaml:"-"` // Set when includes or overlays are used
 }
 
 type ProtectionConfig struct {
-	ExclusionList []string `yaml:"exclusion_list"`
+	ExclusionList []string `yaml:"exclusion_list" json:"exclusion_list"`
 }
 
 type SafeToKillConfig struct {
-	Bloatware     []string `yaml:"bloatware"`
-	ChatApps      []string `yaml:"chat_apps"`
-	GameLaunchers []string `yaml:"game_launchers"`
-	Utilities     []string `yaml:"utilities"`
+	Bloatware     []string `yaml:"bloatware" json:"bloatware"`
+	ChatApps      []string `yaml:"chat_apps" json:"chat_apps"`
+	GameLaunchers []string `yaml:"game_launchers" json:"game_launchers"`
+	Utilities     []string `yaml:"utilities" json:"utilities"`
 }
 
 type PresetConfig struct {
-	Name string   `yaml:"name"`
-	Key  string   `yaml:"key"`
-	Apps []string `yaml:"apps"`
+	Name   string      `yaml:"name" json:"name"`
+	Key    string      `yaml:"key" json:"key"`
+	Apps   []string    `yaml:"apps" json:"apps"`
+	Source configLayer `yaml:"-" json:"-"`
 }
 
 type ThemeConfig struct {
-	Name      string `yaml:"name,omitempty"`
-	Base      string `yaml:"base"`
-	Surface   string `yaml:"surface"`
-	Text      string `yaml:"text"`
-	Highlight string `yaml:"highlight"`
-	Select    string `yaml:"select"`
-	Kill      string `yaml:"kill"`
-	Restore   string `yaml:"restore"`
-	Suspend   string `yaml:"suspend"`
-	Warn      string `yaml:"warn"`
+	Name      string `yaml:"name,omitempty" json:"name,omitempty"`
+	Base      string `yaml:"base" json:"base"`
+	Surface   string `yaml:"surface" json:"surface"`
+	Text      string `yaml:"text" json:"text"`
+	Highlight string `yaml:"highlight" json:"highlight"`
+	Select    string `yaml:"select" json:"select"`
+	Kill      string `yaml:"kill" json:"kill"`
+	Restore   string `yaml:"restore" json:"restore"`
+	Suspend   string `yaml:"suspend" json:"suspend"`
+	Warn      string `yaml:"warn" json:"warn"`
 }
 
 type HotkeyConfig struct {
@@ -279,15 +269,41 @@ type HotkeyConfig struct {
 	RestoreMode []string `yaml:"restore_mode"`
 	Quit        []string `yaml:"quit"`
 	Help        []string `yaml:"help"`
+
+	// Keys below default to the built-in bindings when left out
+	NewItem       []string `yaml:"new_item,omitempty"`
+	EditItem      []string `yaml:"edit_item,omitempty"`
+	DeleteItem    []string `yaml:"delete_item,omitempty"`
+	SearchProcess []string `yaml:"search_process,omitempty"`
+	ThemeMenu     []string `yaml:"theme_menu,omitempty"`
+	PresetMenu    []string `yaml:"preset_menu,omitempty"`
+	SafelistMenu  []string `yaml:"safelist_menu,omitempty"`
+	History       []string `yaml:"history,omitempty"`
+	Undo          []string `yaml:"undo,omitempty"`
+	Export        []string `yaml:"export,omitempty"`
+	Import        []string `yaml:"import,omitempty"`
+	FindExec      []string `yaml:"find_executable,omitempty"`
+	RepairPaths   []string `yaml:"repair_paths,omitempty"`
+	Details       []string `yaml:"details,omitempty"`
+	Sort          []string `yaml:"sort,omitempty"`
+	SortReverse   []string `yaml:"sort_reverse,omitempty"`
+	Filter        []string `yaml:"filter,omitempty"`
+	Explorer      []string `yaml:"process_explorer,omitempty"`
+	Palette       []string `yaml:"command_palette,omitempty"`
+	LogProblems   []string `yaml:"log_problems,omitempty"`
+	SaveLog       []string `yaml:"save_log,omitempty"`
 }
 
 type AppEntry struct {
-	Name        string         `yaml:"name"`
-	ProcessName string         `yaml:"process_name"`
-	ExecPath    string         `yaml:"exec_path"`
-	Selected    bool           `yaml:"selected"`
-	SafetyLevel string         `yaml:"safety_level,omitempty"` // NEW: "protected", "safe", "caution"
-	PIDs        map[int32]bool `yaml:"-"`
+	Name        string         `yaml:"name" json:"name"`
+	ProcessName string         `yaml:"process_name,omitempty" json:"process_name,omitempty"`
+	ExecPath    string         `yaml:"exec_path,omitempty" json:"exec_path,omitempty"`
+	Args        []string       `yaml:"args,omitempty" json:"args,omitempty"`
+	Selected    bool           `yaml:"selected,omitempty" json:"selected,omitempty"`
+	SafetyLevel string         `yaml:"safety_level,omitempty" json:"safety_level,omitempty"` // NEW: "protected", "safe", "caution"
+	PIDs        map[int32]bool `yaml:"-" json:"-"`
+
+	Sources map[string]configLayer `yaml:"-" json:"-"` // Layer each field came from
 }
 
 // profileItem represents a profile file in the import list
@@ -306,53 +322,6 @@ func (i profileItem) Description() string {
 }
 func (i profileItem) FilterValue() string { return i.filename }
 
-// ProcessStats holds resource usage information
-type ProcessStats struct {
-	CPUPercent float64
-	RAMMB      uint64
-	IsRunning  bool
-}
-
-// StatsCache caches process statistics to reduce overhead
-type StatsCache struct {
-	stats     map[string]ProcessStats
-	timestamp map[string]time.Time
-	mutex     sync.RWMutex
-	ttl       time.Duration
-}
-
-// NewStatsCache creates a new stats cache with 2-second TTL
-func NewStatsCache() *StatsCache {
-	return &StatsCache{
-		stats:     make(map[string]ProcessStats),
-		timestamp: make(map[string]time.Time),
-		ttl:       2 * time.Second,
-	}
-}
-
-// Get retrieves stats, using cache if valid
-func (sc *StatsCache) Get(processName string) ProcessStats {
-	sc.mutex.RLock()
-	if ts, exists := sc.timestamp[processName]; exists {
-		if time.Since(ts) < sc.ttl {
-			stats := sc.stats[processName]
-			sc.mutex.RUnlock()
-			return stats
-		}
-	}
-	sc.mutex.RUnlock()
-
-	// Cache miss or expired, fetch new stats
-	stats := getProcessStats(processName)
-
-	sc.mutex.Lock()
-	sc.stats[processName] = stats
-	sc.timestamp[processName] = time.Now()
-	sc.mutex.Unlock()
-
-	return stats
-}
-
 // OperationType represents different types of operations
 type OperationType int
 
@@ -379,12 +348,34 @@ func (ot OperationType) String() string {
 	}
 }
 
+// undoAction returns the action mode that reverses this operation
+func (ot OperationType) undoAction() string {
+	switch ot {
+	case OpKill:
+		return "restore"
+	case OpSuspend:
+		return "resume"
+	case OpResume:
+		return "suspend"
+	case OpRestore:
+		return "kill"
+	default:
+		return ""
+	}
+}
+
 // AppHistoryItem stores information about an app in history
 type AppHistoryItem struct {
 	Name        string
 	ProcessName string
 	ExecPath    string
-	PIDs        []int32 // For suspend operations
+	Args        []string `json:",omitempty"`
+	PIDs        []int32  // For suspend operations
+}
+
+// entry returns the history item as an app entry for variable expansion
+func (h AppHistoryItem) entry() AppEntry {
+	return AppEntry{Name: h.Name, ProcessName: h.ProcessName, ExecPath: h.ExecPath, Args: h.Args}
 }
 
 // HistoryEntry represents a single operation in history
@@ -405,21 +396,21 @@ type SessionHistory struct {
 
 // ProfileMetadata stores information about an exported profile
 type ProfileMetadata struct {
-	Version           string    `json:"version"`
-	SceneShiftVersion string    `json:"sceneshift_version"`
-	ExportDate        time.Time `json:"export_date"`
-	Description       string    `json:"description"`
-	Author            string    `json:"author,omitempty"`
+	Version           string    `json:"version" yaml:"version"` // Profile format, see currentProfileFormat
+	SceneShiftVersion string    `json:"sceneshift_version" yaml:"sceneshift_version"`
+	ExportDate        time.Time `json:"export_date" yaml:"export_date"`
+	Description       string    `json:"description" yaml:"description"`
+	Author            string    `json:"author,omitempty" yaml:"author,omitempty"`
 }
 
 // ConfigProfile represents an exportable configuration
 type ConfigProfile struct {
-	Metadata   ProfileMetadata  `json:"metadata"`
-	Apps       []AppEntry       `json:"apps"`
-	Presets    []PresetConfig   `json:"presets"`
-	Theme      ThemeConfig      `json:"theme"`
-	Protection ProtectionConfig `json:"protection"`
-	SafeToKill SafeToKillConfig `json:"safe_to_kill"`
+	Metadata   ProfileMetadata  `json:"metadata" yaml:"metadata"`
+	Apps       []AppEntry       `json:"apps" yaml:"apps"`
+	Presets    []PresetConfig   `json:"presets" yaml:"presets"`
+	Theme      ThemeConfig      `json:"theme" yaml:"theme,omitempty"`
+	Protection ProtectionConfig `json:"protection" yaml:"protection,omitempty"`
+	SafeToKill SafeToKillConfig `json:"safe_to_kill" yaml:"safe_to_kill,omitempty"`
 }
 
 // NewSessionHistory creates a new session history with default max size
@@ -470,6 +461,7 @@ var themePresets = []ThemeConfig{
 	{Name: "Nord", Base: "#2e3440", Surface: "#3b4252", Text: "#eceff4", Highlight: "#88c0d0", Select: "#81a1c1", Kill: "#bf616a", Restore: "#a3be8c", Suspend: "#ebcb8b", Warn: "#d08770"},
 	{Name: "Gruvbox Dark", Base: "#282828", Surface: "#3c3836", Text: "#ebdbb2", Highlight: "#458588", Select: "#d79921", Kill: "#cc241d", Restore: "#98971a", Suspend: "#fabd2f", Warn: "#d65d0e"},
 	{Name: "Cyberpunk", Base: "#000b1e", Surface: "#05162a", Text: "#00ff9f", Highlight: "#00b8ff", Select: "#fcee0a", Kill: "#ff003c", Restore: "#00ff9f", Suspend: "#bd00ff", Warn: "#fcee0a"},
+	highContrastTheme,
 }
 
 // --- KeyMap ---
@@ -497,6 +489,16 @@ type keyMap struct {
 	Undo         key.Binding
 	Export       key.Binding
 	Import       key.Binding
+	FindExec     key.Binding
+	RepairPaths  key.Binding
+	Details      key.Binding
+	Sort         key.Binding
+	SortReverse  key.Binding
+	Filter       key.Binding
+	Explorer     key.Binding
+	Palette      key.Binding
+	LogProblems  key.Binding
+	SaveLog      key.Binding
 }
 
 func (k keyMap) ShortHelp() []key.Binding {
@@ -510,19 +512,128 @@ func (k keyMap) FullHelp() [][]key.Binding {
 		{k.NewItem, k.EditItem, k.DeleteItem},
 		{k.Kill, k.Suspend, k.Resume, k.Restore},
 		{k.ThemeMenu, k.PresetMenu, k.SafelistMenu},
-		{k.History, k.Undo, k.Export, k.Import},
+		{k.History, k.Undo, k.Export, k.Import, k.RepairPaths},
+		{k.Details, k.Sort, k.SortReverse, k.Filter, k.Explorer, k.Palette},
+	}
+}
+
+// newKeyMap builds the key bindings from the configured hotkeys. Help text
+// is generated from the keys actually bound.
+func newKeyMap(hk HotkeyConfig) keyMap {
+	return keyMap{
+		Up:           newBinding(hk.Up, "up", "up", "k"),
+		Down:         newBinding(hk.Down, "down", "down", "j"),
+		Toggle:       newBinding(hk.Toggle, "toggle", "space"),
+		SelectAll:    newBinding(hk.SelectAll, "all", "a"),
+		DeselectAll:  newBinding(hk.DeselectAll, "none", "x"),
+		Kill:         newBinding(hk.KillMode, "KILL", "K"),
+		Suspend:      newBinding(hk.SuspendMode, "SUSPEND", "S"),
+		Resume:       newBinding(hk.ResumeMode, "RESUME", "U"),
+		Restore:      newBinding(hk.RestoreMode, "RESTORE", "R"),
+		Quit:         newBinding(hk.Quit, "quit", "q", "ctrl+c"),
+		Help:         newBinding(hk.Help, "help", "?"),
+		NewItem:      newBinding(hk.NewItem, "new", "n"),
+		EditItem:     newBinding(hk.EditItem, "edit", "e"),
+		DeleteItem:   newBinding(hk.DeleteItem, "delete", "d"),
+		SearchProc:   newBinding(hk.SearchProcess, "search running", "ctrl+f"),
+		ThemeMenu:    newBinding(hk.ThemeMenu, "theme", "t"),
+		PresetMenu:   newBinding(hk.PresetMenu, "presets", "p"),
+		SafelistMenu: newBinding(hk.SafelistMenu, "exclusion list", "w"),
+		History:      newBinding(hk.History, "history", "h"),
+		Undo:         newBinding(hk.Undo, "undo", "u", "ctrl+z"),
+		Export:       newBinding(hk.Export, "export", "ctrl+e"),
+		Import:       newBinding(hk.Import, "import", "i"),
+		FindExec:     newBinding(hk.FindExec, "find installed", "ctrl+o"),
+		RepairPaths:  newBinding(hk.RepairPaths, "repair paths", "F"),
+		Details:      newBinding(hk.Details, "details", "enter"),
+		Sort:         newBinding(hk.Sort, "sort", "o"),
+		SortReverse:  newBinding(hk.SortReverse, "reverse sort", "O"),
+		Filter:       newBinding(hk.Filter, "filter", "/"),
+		Explorer:     newBinding(hk.Explorer, "processes", "P"),
+		Palette:      newBinding(hk.Palette, "command palette", "ctrl+p"),
+		LogProblems:  newBinding(hk.LogProblems, "problems only", "e"),
+		SaveLog:      newBinding(hk.SaveLog, "save log", "s"),
+	}
+}
+
+// newBinding binds the configured keys, falling back to defaults when none
+// are configured
+func newBinding(keys []string, desc string, defaults ...string) key.Binding {
+	if len(keys) == 0 {
+		keys = defaults
+	}
+	bound := make([]string, len(keys))
+	for i, k := range keys {
+		if k == "space" {
+			k = " "
+		}
+		bound[i] = k
+	}
+	return key.NewBinding(key.WithKeys(bound...), key.WithHelp(helpKeys(bound), desc))
+}
+
+// helpKeys formats bound keys for display, e.g. "↑/k" or "u/Ctrl+Z"
+func helpKeys(keys []string) string {
+	var labels []string
+	seen := map[string]bool{}
+	for _, k := range keys {
+		label := k
+		switch {
+		case k == " ":
+			label = "Space"
+		case k == "up" && display.ascii:
+			label = "Up"
+		case k == "down" && display.ascii:
+			label = "Down"
+		case k == "up":
+			label = "↑"
+		case k == "down":
+			label = "↓"
+		case strings.HasPrefix(k, "ctrl+"):
+			label = "Ctrl+" + strings.ToUpper(strings.TrimPrefix(k, "ctrl+"))
+		}
+		if !seen[label] {
+			seen[label] = true
+			labels = append(labels, label)
+		}
 	}
+	return strings.Join(labels, "/")
+}
+
+// hint returns the help key of a binding for use in on-screen hints
+func hint(b key.Binding) string {
+	return b.Help().Key
+}
+
+// boundTo returns the hotkeys field name of the menu binding that uses a
+// key, or "" if the key is free
+func (k keyMap) boundTo(pressed string) string {
+	for _, b := range menuBindings(k) {
+		for _, bk := range b.binding.Keys() {
+			if bk == pressed {
+				return b.name
+			}
+		}
+	}
+	return ""
 }
 
 // --- List Items ---
 
 type processItem struct {
-	name string
-	exe  string
-	path string
+	name   string
+	exe    string
+	path   string
+	marked bool // Marked for bulk add
+}
+
+func (i processItem) Title() string {
+	if i.marked {
+		return glyphs("✓ ") + i.name
+	}
+	return i.name
 }
 
-func (i processItem) Title() string       { return i.name }
 func (i processItem) Description() string { return i.exe }
 func (i processItem) FilterValue() string { return i.name }
 
@@ -555,9 +666,19 @@ const (
 	stateUndoConfirm
 	stateProfileExport
 	stateProfileImport
+	stateConfigRecovery
+	stateDiagnostics
+	stateExecPicker
+	stateRepairPaths
+	stateImportReview
+	stateAppDetail
+	stateProcessExplorer
+	stateBulkAdd
+	statePalette
 )
 
-type tickMsg time.Time
+// tickMsg counts down one second of the countdown with the given id
+type tickMsg struct{ id int }
 
 type model struct {
 	config   Config
@@ -570,8 +691,27 @@ type model struct {
 	mode          string
 	currentState  state
 	isFirstLaunch bool
-	statsCache    *StatsCache
+	cpuSampler    *cpuSampler
+	sortColumn    appColumn
+	sortDesc      bool
+	filtering     bool // Typing in filterInput
+	filterInput   textinput.Model
+	tableOffset   int // First app row shown
+	statsHistory  map[string][]statsSample
+	detailApp     string // Name of the app in the detail view
+	detailPID     int32  // Selected process in the detail view
+	detailConfirm string // Action awaiting confirmation for detailPID
+	detailMessage string
+	explorer      explorerState
+	palette       paletteState
+	statsID       int                 // Current refresh loop, see restartStats
+	appStats      map[string]appStats // Latest figures keyed by app name
+	systemStats   systemStats
+	statsUpdated  time.Time
 	history       *SessionHistory
+	audit         *AuditLogger
+	auditErr      error  // First failure to write the audit log
+	trigger       string // What selected the current targets, for the audit log
 
 	// Editor Logic
 	inputs     []textinput.Model
@@ -584,8 +724,10 @@ type model struct {
 	tempPresetApps   map[string]bool
 
 	// Safelist Logic
-	safelistCursor int
-	safelistInput  textinput.Model
+	safelistCursor  int
+	safelistInput   textinput.Model
+	safelistTab     int // 0: exclusion list, 1: safe-to-kill entries, 2: packs
+	safelistMessage string
 
 	historyCursor int
 	undoMessage   string
@@ -593,13 +735,24 @@ type model struct {
 	// List Logic
 	procList    list.Model
 	allProcs    []list.Item
+	pickMarked  map[string]bool // Process names marked for bulk add
+	bulk        bulkAddState
 	searchInput textinput.Model
 	themeList   list.Model
 
 	// Countdown & Progress
 	countdown   int
+	countdownID int // Current countdown, see startCountdown
 	progPercent float64
 	logs        []string
+	logDetails  map[int]logDetail // Matched processes and errors, by m.logs index
+
+	// Operation Log
+	logPane     viewport.Model
+	logCursor   int          // Index into m.logs
+	logErrors   bool         // Show problems only
+	logExpanded map[int]bool // Lines showing their detail
+	logMessage  string
 
 	// Stats
 	startRAM uint64
@@ -610,44 +763,112 @@ type model struct {
 	profileDescription string
 	profileAuthor      string
 	profileMessage     string
-	profileList        list.Model
+	exportVariables    bool   // Rewrite absolute paths as ${VAR} on export
+	exportFormat       string // "json" or "yaml"
+	exportItems        []exportItem
+	exportCursor       int
+	exportConfirmPath  string // Existing file the next Enter will replace
+
+	// Preset Editing
+	presetMessage string
+	profileList   list.Model
+
+	// Status line shown on the main menu
+	statusMessage string
+
+	// Config Persistence
+	saveErr       error
+	configBroken  bool  // config.yaml failed to load; saves are refused
+	configLoadErr error // Why config.yaml failed to load
+	backups       []string
+	backupCursor  int
+
+	// Config Diagnostics
+	diagnostics []Diagnostic
+
+	// Live Reload
+	configSum         string // SHA256 of config.yaml as last loaded or saved
+	themeSum          string
+	badReloadSum      string // Outside edit that failed to load, not retried
+	reloadConflict    bool   // Save refused; files changed on disk meanwhile
+	quitAfterConflict bool
+	conflictErr       error
+
+	// Executable Discovery
+	discovering    bool
+	discoveryID    int
+	discoverFor    string // Process name searched from the app editor
+	execCandidates []execCandidate
+	execCursor     int
+	repairs        []pathRepair
+	repairCursor   int
+
+	// Profile Import
+	importing           ConfigProfile
+	importFile          string
+	importFormat        string // Profile format before upgrading
+	importItems         []importItem
+	importCursor        int
+	importRemoveMissing bool // Replace mode: drop what the profile lacks
 }
 
 // --- Init & Config Loading ---
 
-func loadConfig() (Config, bool, error) {
-	f, err := os.ReadFile("config.yaml")
+// loadConfig reads config.yaml, creating it on first launch. Problems that
+// do not prevent loading, such as a failed migration write, are returned as
+// warnings.
+func loadConfig() (Config, bool, []string, error) {
+	var warnings []string
+
+	f, err := os.ReadFile(configFile)
 	if errors.Is(err, os.ErrNotExist) {
-		cfg, _ := createDefaultConfig()
-		return cfg, true, nil
+		cfg, err := createDefaultConfig()
+		if err != nil {
+			warnings = append(warnings, err.Error())
+		}
+		return cfg, true, warnings, nil
 	} else if err != nil {
-		return Config{}, false, fmt.Errorf("could not read config.yaml: %w", err)
+		return Config{}, false, nil, fmt.Errorf("could not read %s: %w", configFile, err)
 	}
 
-	var cfg Config
-	err = yaml.Unmarshal(f, &cfg)
+	cfg, fromVersion, err := migrateConfig(f)
 	if err != nil {
-		return Config{}, false, fmt.Errorf("could not parse config.yaml: %w", err)
+		return Config{}, false, nil, fmt.Errorf("could not parse %s: %w", configFile, err)
+	}
+
+	// Rewrite configs from older schema versions, keeping the original
+	if fromVersion < currentSchemaVersion {
+		if _, err := backupBeforeMigration(f, fromVersion); err != nil {
+			warnings = append(warnings, fmt.Sprintf("migrated config not saved, backup failed: %v", err))
+		} else if err := writeConfigFile(cfg); err != nil {
+			warnings = append(warnings, fmt.Sprintf("could not save migrated config: %v", err))
+		}
+	}
+
+	if cfg.hasLayers() {
+		cfg, err = resolveLayers(cfg)
+		if err != nil {
+			return Config{}, false, nil, fmt.Errorf("could not load %s: %w", configFile, err)
+		}
 	}
 
-	// Migrate old v2.1.0 config to v2.1.1
-	if migrateOldConfig(&cfg) {
-		// Save migrated config
-		fOut, err := os.Create("config.yaml")
-		if err == nil {
-			defer fOut.Close()
-			encoder := yaml.NewEncoder(fOut)
-			encoder.SetIndent(2)
-			_ = encoder.Encode(cfg)
+	cfg.Policy = loadPolicy()
+	loadPacks(&cfg)
+
+	// Apps added by hand may lack a safety level; fill it in without saving
+	for i := range cfg.Apps {
+		if cfg.Apps[i].SafetyLevel == "" {
+			cfg.Apps[i].SafetyLevel = detectSafetyLevel(cfg.Apps[i].ProcessName, &cfg)
 		}
 	}
 
 	loadTheme(&cfg)
-	return cfg, false, nil
+	return cfg, false, warnings, nil
 }
 
 func createDefaultConfig() (Config, error) {
 	defaultCfg := Config{
+		SchemaVersion: currentSchemaVersion,
 		Hotkeys: HotkeyConfig{
 			Up:          []string{"up", "k"},
 			Down:        []string{"down", "j"},
@@ -660,6 +881,28 @@ func createDefaultConfig() (Config, error) {
 			RestoreMode: []string{"R"},
 			Quit:        []string{"q", "ctrl+c"},
 			Help:        []string{"?"},
+
+			NewItem:       []string{"n"},
+			EditItem:      []string{"e"},
+			DeleteItem:    []string{"d"},
+			SearchProcess: []string{"ctrl+f"},
+			ThemeMenu:     []string{"t"},
+			PresetMenu:    []string{"p"},
+			SafelistMenu:  []string{"w"},
+			History:       []string{"h"},
+			Undo:          []string{"u", "ctrl+z"},
+			Export:        []string{"ctrl+e"},
+			Import:        []string{"i"},
+			FindExec:      []string{"ctrl+o"},
+			RepairPaths:   []string{"F"},
+			Details:       []string{"enter"},
+			Sort:          []string{"o"},
+			SortReverse:   []string{"O"},
+			Filter:        []string{"/"},
+			Explorer:      []string{"P"},
+			Palette:       []string{"ctrl+p"},
+			LogProblems:   []string{"e"},
+			SaveLog:       []string{"s"},
 		},
 		Presets: []PresetConfig{},
 		Apps:    []AppEntry{},
@@ -667,89 +910,90 @@ func createDefaultConfig() (Config, error) {
 			ExclusionList: getDefaultProtectionList(),
 		},
 		SafeToKill: getDefaultSafeToKill(),
+		Policy:     loadPolicy(),
 	}
 
-	f, err := os.Create("config.yaml")
-	if err != nil {
-		return defaultCfg, nil
-	}
-	defer f.Close()
-
-	encoder := yaml.NewEncoder(f)
-	encoder.SetIndent(2)
-	_ = encoder.Encode(defaultCfg)
 	loadTheme(&defaultCfg)
+	if err := writeConfigFile(defaultCfg); err != nil {
+		return defaultCfg, fmt.Errorf("could not create default config: %w", err)
+	}
 	return defaultCfg, nil
 }
 
 func loadTheme(cfg *Config) {
 	cfg.Theme = themePresets[0]
-	fTheme, err := os.ReadFile("theme.yaml")
+	fTheme, err := os.ReadFile(themeFile())
 	if err == nil {
 		_ = yaml.Unmarshal(fTheme, &cfg.Theme)
 	}
+	applyDisplay(cfg)
 }
 
-func (m model) saveConfig() {
-	f, err := os.Create("config.yaml")
-	if err == nil {
-		defer f.Close()
-		encoder := yaml.NewEncoder(f)
-		encoder.SetIndent(2)
-		_ = encoder.Encode(m.config)
+// saveConfig persists config.yaml and theme.yaml, reporting failures on the
+// main menu. Saving is refused while an unreadable config.yaml is awaiting
+// recovery, so the broken file is never silently overwritten.
+func (m *model) saveConfig() {
+	if m.configBroken {
+		m.saveErr = fmt.Errorf("%s could not be loaded; changes are not being saved", filepath.Base(configFile))
+		m.statusMessage = glyphs("❌ ") + m.saveErr.Error()
+		return
 	}
-	fT, err := os.Create("theme.yaml")
+	if m.changedOnDisk() {
+		m.reloadConflict = true
+		return
+	}
+
+	err := writeConfigFile(m.config)
 	if err == nil {
-		defer fT.Close()
-		encT := yaml.NewEncoder(fT)
-		encT.SetIndent(2)
-		_ = encT.Encode(m.config.Theme)
+		err = writeThemeFile(savedTheme(m.config.Theme))
+	}
+	m.syncFileSums()
+	if err != nil {
+		m.saveErr = err
+		m.statusMessage = glyphs("❌ Save failed: ") + err.Error()
+		return
+	}
+	if m.saveErr != nil {
+		m.saveErr = nil
+		m.statusMessage = ""
+	}
+}
+
+// logAudit writes a record to the audit log, reporting a failure on the
+// main menu
+func (m *model) logAudit(rec AuditRecord) {
+	m.noteAuditErr(m.audit.Log(rec))
+}
+
+// noteAuditErr reports the first failure to write the audit log. Later
+// failures almost always share its cause, so they are not repeated.
+func (m *model) noteAuditErr(err error) {
+	if err == nil || m.auditErr != nil {
+		return
 	}
+	m.auditErr = err
+	msg := glyphs("❌ Audit log could not be written: ") + err.Error()
+	if m.statusMessage != "" {
+		msg = m.statusMessage + glyphs(" • ") + msg
+	}
+	m.statusMessage = msg
 }
 
 func initialModel() model {
-	cfg, firstLaunch, err := loadConfig()
+	migratedFrom, migrateErr := migrateLegacyConfig()
+
+	cfg, firstLaunch, warnings, err := loadConfig()
 	if err != nil {
-		cfg = Config{}
+		cfg = Config{Policy: loadPolicy()}
 		loadTheme(&cfg)
 	}
 
-	toggleKeys := cfg.Hotkeys.Toggle
-	for i, k := range toggleKeys {
-		if k == "space" {
-			toggleKeys[i] = " "
-		}
-	}
-
-	keys := keyMap{
-		Up:           key.NewBinding(key.WithKeys(cfg.Hotkeys.Up...), key.WithHelp("↑/k", "up")),
-		Down:         key.NewBinding(key.WithKeys(cfg.Hotkeys.Down...), key.WithHelp("↓/j", "down")),
-		Toggle:       key.NewBinding(key.WithKeys(toggleKeys...), key.WithHelp("Space", "toggle")),
-		SelectAll:    key.NewBinding(key.WithKeys(cfg.Hotkeys.SelectAll...), key.WithHelp("a", "all")),
-		DeselectAll:  key.NewBinding(key.WithKeys(cfg.Hotkeys.DeselectAll...), key.WithHelp("x", "none")),
-		Kill:         key.NewBinding(key.WithKeys(cfg.Hotkeys.KillMode...), key.WithHelp("K", "KILL")),
-		Restore:      key.NewBinding(key.WithKeys(cfg.Hotkeys.RestoreMode...), key.WithHelp("R", "RESTORE")),
-		Quit:         key.NewBinding(key.WithKeys(cfg.Hotkeys.Quit...), key.WithHelp("q", "quit")),
-		Help:         key.NewBinding(key.WithKeys(cfg.Hotkeys.Help...), key.WithHelp("?", "help")),
-		NewItem:      key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "new")),
-		EditItem:     key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "edit")),
-		DeleteItem:   key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "delete")),
-		SearchProc:   key.NewBinding(key.WithKeys("ctrl+f"), key.WithHelp("ctrl+f", "search running")),
-		ThemeMenu:    key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "theme")),
-		PresetMenu:   key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "presets")),
-		Suspend:      key.NewBinding(key.WithKeys(cfg.Hotkeys.SuspendMode...), key.WithHelp("S", "SUSPEND")),
-		Resume:       key.NewBinding(key.WithKeys(cfg.Hotkeys.ResumeMode...), key.WithHelp("U", "RESUME")),
-		SafelistMenu: key.NewBinding(key.WithKeys("w"), key.WithHelp("w", "exclusion list")),
-		History:      key.NewBinding(key.WithKeys("h"), key.WithHelp("h", "history")),
-		Undo:         key.NewBinding(key.WithKeys("u", "ctrl+z"), key.WithHelp("u/Ctrl+Z", "undo")),
-		Export:       key.NewBinding(key.WithKeys("ctrl+e"), key.WithHelp("Ctrl+E", "export")),
-		Import:       key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "import")),
-	}
-
-	prog := progress.New(
+	keys := newKeyMap(cfg.Hotkeys)
+
+	prog := progress.New(progressOptions(
 		progress.WithGradient(cfg.Theme.Kill, cfg.Theme.Highlight),
 		progress.WithWidth(40),
-	)
+	)...)
 
 	// App Inputs Init
 	appInputs := make([]textinput.Model, 3)
@@ -758,12 +1002,12 @@ func initialModel() model {
 	}
 
 	sInput := textinput.New()
-	sInput.Prompt = "🔍 Search: "
+	sInput.Prompt = glyphs("🔍 Search: ")
 	sInput.Placeholder = "Type to filter..."
 	sInput.Focus()
 
 	safeInput := textinput.New()
-	safeInput.Prompt = "➕ Add: "
+	safeInput.Prompt = glyphs("➕ Add: ")
 	safeInput.Placeholder = "processname.exe"
 
 	lProc := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
@@ -771,6 +1015,7 @@ func initialModel() model {
 	lProc.SetShowTitle(false)
 	lProc.SetFilteringEnabled(false)
 	lProc.DisableQuitKeybindings()
+	plainList(&lProc)
 
 	themeItems := []list.Item{}
 	for _, t := range themePresets {
@@ -779,19 +1024,41 @@ func initialModel() model {
 	lTheme := list.New(themeItems, list.NewDefaultDelegate(), 0, 0)
 	lTheme.Title = "Select Theme"
 	lTheme.SetShowHelp(false)
+	plainList(&lTheme)
 
 	lProfile := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
 	lProfile.Title = "Select Profile"
 	lProfile.SetShowHelp(false)
 	lProfile.SetFilteringEnabled(false)
 	lProfile.DisableQuitKeybindings()
+	plainList(&lProfile)
 
 	initialState := stateMenu
 	if firstLaunch {
 		initialState = stateThemePicker
 	}
 
-	return model{
+	var diagnostics []Diagnostic
+	if err != nil {
+		initialState = stateConfigRecovery
+	} else if !firstLaunch {
+		diagnostics = validateConfigFiles()
+		if len(diagnostics) > 0 {
+			initialState = stateDiagnostics
+		}
+	}
+
+	var status []string
+	if migrateErr != nil {
+		status = append(status, fmt.Sprintf(glyphs("⚠️ Config migration failed: %v"), migrateErr))
+	} else if migratedFrom != "" {
+		status = append(status, fmt.Sprintf(glyphs("📁 Config copied from %s to %s"), migratedFrom, configDir()))
+	}
+	if len(warnings) > 0 {
+		status = append(status, glyphs("⚠️ ")+strings.Join(warnings, "; "))
+	}
+
+	m := model{
 		config:        cfg,
 		keys:          keys,
 		help:          help.New(),
@@ -804,14 +1071,23 @@ func initialModel() model {
 		procList:      lProc,
 		themeList:     lTheme,
 		safelistInput: safeInput,
-		statsCache:    NewStatsCache(),
+		cpuSampler:    newCPUSampler(),
+		explorer:      explorerState{collapsed: map[int32]bool{}, suspended: map[int32]bool{}},
 		history:       NewSessionHistory(),
+		audit:         NewAuditLogger(cfg.Audit),
 		profileList:   lProfile,
+		statusMessage: strings.Join(status, glyphs(" • ")),
+		configBroken:  err != nil,
+		configLoadErr: err,
+		backups:       listConfigBackups(),
+		diagnostics:   diagnostics,
 	}
+	m.syncFileSums()
+	return m
 }
 
 func (m model) Init() tea.Cmd {
-	return textinput.Blink
+	return tea.Batch(textinput.Blink, watchConfigCmd(), m.collectStatsCmd())
 }
 
 func getRAMUsageMB() uint64 {
@@ -822,43 +1098,6 @@ func getRAMUsageMB() uint64 {
 	return v.Used / 1024 / 1024
 }
 
-// getProcessStats retrieves CPU and RAM stats for a process
-func getProcessStats(processName string) ProcessStats {
-	stats := ProcessStats{}
-
-	procs, err := process.Processes()
-	if err != nil {
-		return stats
-	}
-
-	targets := strings.Split(processName, ",")
-	for _, p := range procs {
-		name, err := p.Name()
-		if err != nil {
-			continue
-		}
-
-		for _, target := range targets {
-			target = strings.TrimSpace(target)
-			if strings.EqualFold(name, target) {
-				stats.IsRunning = true
-
-				// Get CPU usage
-				if cpu, err := p.CPUPercent(); err == nil {
-					stats.CPUPercent += cpu
-				}
-
-				// Get memory usage
-				if mem, err := p.MemoryInfo(); err == nil {
-					stats.RAMMB += mem.RSS / 1024 / 1024
-				}
-			}
-		}
-	}
-
-	return stats
-}
-
 // pidExists checks if a PID is currently active
 func pidExists(pid int32) bool {
 	p, err := process.NewProcess(pid)
@@ -871,44 +1110,6 @@ func pidExists(pid int32) bool {
 	return err == nil && running
 }
 
-// getProcessStatus determines the current state of a process
-// Returns: "running", "suspended", "not_found"
-func getProcessStatus(app AppEntry) string {
-	// If we have PIDs recorded, the process is suspended
-	if len(app.PIDs) > 0 {
-		// Verify at least one PID still exists
-		for pid := range app.PIDs {
-			if pidExists(pid) {
-				return "suspended"
-			}
-		}
-		// All PIDs are gone
-		return "not_found"
-	}
-
-	// Check if process is currently running
-	procs, err := process.Processes()
-	if err != nil {
-		return "not_found"
-	}
-
-	targets := strings.Split(app.ProcessName, ",")
-	for _, p := range procs {
-		name, err := p.Name()
-		if err != nil {
-			continue
-		}
-		for _, target := range targets {
-			target = strings.TrimSpace(target)
-			if strings.EqualFold(name, target) {
-				return "running"
-			}
-		}
-	}
-
-	return "not_found"
-}
-
 // getStatusIcon returns the appropriate icon for process status
 func getStatusIcon(status string) string {
 	switch status {
@@ -931,6 +1132,7 @@ func (m *model) recordKillOperation(apps []AppEntry, successCount, failCount int
 			Name:        app.Name,
 			ProcessName: app.ProcessName,
 			ExecPath:    app.ExecPath,
+			Args:        app.Args,
 		})
 	}
 
@@ -957,6 +1159,7 @@ func (m *model) recordSuspendOperation(apps []AppEntry, successCount, failCount
 			Name:        app.Name,
 			ProcessName: app.ProcessName,
 			ExecPath:    app.ExecPath,
+			Args:        app.Args,
 			PIDs:        pids,
 		})
 	}
@@ -984,6 +1187,7 @@ func (m *model) recordResumeOperation(apps []AppEntry, successCount, failCount i
 			Name:        app.Name,
 			ProcessName: app.ProcessName,
 			ExecPath:    app.ExecPath,
+			Args:        app.Args,
 			PIDs:        pids,
 		})
 	}
@@ -1005,6 +1209,7 @@ func (m *model) recordRestoreOperation(apps []AppEntry, successCount, failCount
 			Name:        app.Name,
 			ProcessName: app.ProcessName,
 			ExecPath:    app.ExecPath,
+			Args:        app.Args,
 		})
 	}
 
@@ -1022,8 +1227,10 @@ func (m *model) performUndo() tea.Cmd {
 	entry := m.history.GetLast()
 	if entry == nil {
 		m.logs = []string{"No operations to undo"}
+		m.logDetails = nil
 		m.currentState = stateDone
 		m.mode = "undo"
+		m.openLog()
 		return nil
 	}
 
@@ -1049,29 +1256,56 @@ func (m *model) executeUndo() tea.Cmd {
 		return nil
 	}
 
+	m.mode = "undo"
+
+	// Undo runs the reverse action, which the system policy may forbid
+	action := entry.Operation.undoAction()
+	if err := m.config.Policy.CheckAction(action, len(entry.Apps)); err != nil {
+		m.logAudit(AuditRecord{Action: action, Result: "denied", Error: err.Error(), Trigger: "undo"})
+		m.logs = []string{fmt.Sprintf(glyphs("[🔒 POLICY] Cannot undo %s: %v"), entry.Operation.String(), err)}
+		m.logDetails = nil
+		m.progPercent = 1.0
+		m.currentState = stateDone
+		m.openLog()
+		return nil
+	}
+
 	// Perform undo immediately (not as a command)
 	var msgs []string
+	details := map[int]logDetail{}
 	successCount := 0
 	failCount := 0
 
 	msgs = append(msgs, fmt.Sprintf("Undoing %s operation...", entry.Operation.String()))
 
+	// Called right after the app's line is added to msgs
+	logUndo := func(app AppHistoryItem, targets []AuditTarget, err error) {
+		rec := AuditRecord{Action: action, App: app.Name, Process: app.ProcessName, Targets: targets, Trigger: "undo"}
+		rec.Result, rec.Error = auditResult(err)
+		m.logAudit(rec)
+		details[len(msgs)-1] = logDetail{targets: targets, err: err}
+	}
+
 	switch entry.Operation {
 	case OpKill:
 		// Undo kill = restore processes
 		for _, app := range entry.Apps {
 			if app.ExecPath == "" {
 				msgs = append(msgs, fmt.Sprintf("[SKIP] %s: No executable path", app.Name))
+				logUndo(app, nil, errors.New("no executable path"))
 				failCount++
 				continue
 			}
 
-			cmd := exec.Command(app.ExecPath)
+			resolved := m.config.resolveApp(app.entry())
+			cmd := launchCommand(resolved)
 			if err := cmd.Start(); err != nil {
 				msgs = append(msgs, fmt.Sprintf("[ERR]  %s: %v", app.Name, err))
+				logUndo(app, nil, err)
 				failCount++
 			} else {
 				msgs = append(msgs, fmt.Sprintf("[OK]   Restored %s", app.Name))
+				logUndo(app, []AuditTarget{{PID: int32(cmd.Process.Pid), Exe: resolved.ExecPath}}, nil)
 				successCount++
 			}
 		}
@@ -1085,6 +1319,7 @@ func (m *model) executeUndo() tea.Cmd {
 				continue
 			}
 
+			targets := auditTargetsByPID(app.PIDs)
 			resumed := 0
 			for _, pid := range app.PIDs {
 				if pidExists(pid) {
@@ -1096,19 +1331,20 @@ func (m *model) executeUndo() tea.Cmd {
 
 			if resumed > 0 {
 				msgs = append(msgs, fmt.Sprintf("[OK]   Resumed %s (%d processes)", app.Name, resumed))
+				logUndo(app, targets, nil)
 				successCount++
 
-				// Clear PIDs from config
-				for i := range m.config.Apps {
-					if m.config.Apps[i].Name == app.Name {
-						for _, pid := range app.PIDs {
-							delete(m.config.Apps[i].PIDs, pid)
-						}
-						break
-					}
+				// Clear PIDs from config, or from the explorer for other processes
+				tracked := m.explorer.suspended
+				if appRef := m.findAppByName(app.Name); appRef != nil {
+					tracked = appRef.PIDs
+				}
+				for _, pid := range app.PIDs {
+					delete(tracked, pid)
 				}
 			} else {
 				msgs = append(msgs, fmt.Sprintf("[ERR]  %s: No valid PIDs found", app.Name))
+				logUndo(app, targets, errors.New("no valid PIDs found"))
 				failCount++
 			}
 		}
@@ -1117,17 +1353,59 @@ func (m *model) executeUndo() tea.Cmd {
 		// Undo resume = re-suspend processes
 		for _, app := range entry.Apps {
 			appRef := m.findAppByName(app.Name)
+			if appRef == nil && len(app.PIDs) > 0 {
+				// A process resumed from the explorer that is not a configured app
+				appRef = &AppEntry{Name: app.Name, PIDs: m.explorer.suspended}
+			}
 			if appRef == nil {
 				msgs = append(msgs, fmt.Sprintf("[SKIP] %s: Not found in config", app.Name))
 				failCount++
 				continue
 			}
 
-			if err := suspendProcessByName(appRef.ProcessName, appRef); err != nil {
+			// A single process resumed from the detail view or explorer is
+			// re-suspended alone
+			var targets []AuditTarget
+			var err error
+			if len(app.PIDs) > 0 {
+				// As when suspending a single process, the recorded PIDs may
+				// since have exited and been reused, or become protected
+				names := m.config.expand(app.ProcessName)
+				if names == "" {
+					names = m.config.expand(appRef.ProcessName)
+				}
+				if m.config.isProtected(names) {
+					msgs = append(msgs, fmt.Sprintf(glyphs("[🛡️ PROTECTED] %s cannot be modified"), app.Name))
+					logUndo(app, auditTargetsByPID(app.PIDs), errors.New("protected process"))
+					failCount++
+					continue
+				}
+				var pids []int32
+				for _, pid := range app.PIDs {
+					if err := checkPIDBelongs(pid, names); err != nil {
+						msgs = append(msgs, fmt.Sprintf("[SKIP] %s: PID %d: %v", app.Name, pid, err))
+						continue
+					}
+					pids = append(pids, pid)
+				}
+				if len(pids) == 0 {
+					err = errors.New("no valid PIDs found")
+				} else {
+					targets = auditTargetsByPID(pids)
+					err = suspendPIDs(pids, appRef)
+				}
+			} else {
+				processNames := m.config.expand(appRef.ProcessName)
+				targets = auditTargetsByName(processNames)
+				err = suspendProcessByName(processNames, appRef)
+			}
+			if err != nil {
 				msgs = append(msgs, fmt.Sprintf("[ERR]  %s: %v", app.Name, err))
+				logUndo(app, targets, err)
 				failCount++
 			} else {
 				msgs = append(msgs, fmt.Sprintf("[OK]   Re-suspended %s", app.Name))
+				logUndo(app, targets, nil)
 				successCount++
 			}
 		}
@@ -1137,18 +1415,22 @@ func (m *model) executeUndo() tea.Cmd {
 		for _, app := range entry.Apps {
 			procs, _ := process.Processes()
 			killed := false
+			var targets []AuditTarget
 			for _, p := range procs {
 				name, _ := p.Name()
-				if strings.EqualFold(name, app.ProcessName) {
+				if strings.EqualFold(name, m.config.expand(app.ProcessName)) {
+					targets = append(targets, auditTarget(p))
 					p.Kill()
 					killed = true
 				}
 			}
 			if killed {
 				msgs = append(msgs, fmt.Sprintf("[OK]   Killed %s", app.Name))
+				logUndo(app, targets, nil)
 				successCount++
 			} else {
 				msgs = append(msgs, fmt.Sprintf("[ERR]  %s: Not running", app.Name))
+				logUndo(app, nil, errors.New("not running"))
 				failCount++
 			}
 		}
@@ -1164,8 +1446,10 @@ func (m *model) executeUndo() tea.Cmd {
 
 	// Update model directly
 	m.logs = msgs
+	m.logDetails = details
 	m.progPercent = 1.0
 	m.currentState = stateDone
+	m.openLog()
 
 	return nil
 }
@@ -1187,7 +1471,7 @@ func (m *model) undoCmd(entry *HistoryEntry) tea.Cmd {
 					continue
 				}
 
-				cmd := exec.Command(app.ExecPath)
+				cmd := launchCommand(m.config.resolveApp(app.entry()))
 				if err := cmd.Start(); err != nil {
 					msgs = append(msgs, fmt.Sprintf("[ERR]  %s: %v", app.Name, err))
 					failCount++
@@ -1245,7 +1529,7 @@ func (m *model) undoCmd(entry *HistoryEntry) tea.Cmd {
 					continue
 				}
 
-				if err := suspendProcessByName(appRef.ProcessName, appRef); err != nil {
+				if err := suspendProcessByName(m.config.expand(appRef.ProcessName), appRef); err != nil {
 					msgs = append(msgs, fmt.Sprintf("[ERR]  %s: %v", app.Name, err))
 					failCount++
 				} else {
@@ -1257,7 +1541,7 @@ func (m *model) undoCmd(entry *HistoryEntry) tea.Cmd {
 		case OpRestore:
 			// Undo restore = kill processes
 			for _, app := range entry.Apps {
-				if err := killProcess(app.ProcessName); err != nil {
+				if err := killProcess(m.config.expand(app.ProcessName)); err != nil {
 					msgs = append(msgs, fmt.Sprintf("[ERR]  %s: %v", app.Name, err))
 					failCount++
 				} else {
@@ -1295,9 +1579,9 @@ func (m *model) findAppByName(name string) *AppEntry {
 	return nil
 }
 
-// scanForProfiles scans current directory for profile JSON files
+// scanForProfiles scans the config directory for profile JSON files
 func scanForProfiles() []profileItem {
-	files, err := os.ReadDir(".")
+	files, err := os.ReadDir(configDir())
 	if err != nil {
 		return []profileItem{}
 	}
@@ -1309,22 +1593,23 @@ func scanForProfiles() []profileItem {
 		}
 
 		name := file.Name()
-		if !strings.HasPrefix(name, "sceneshift-profile-") || !strings.HasSuffix(name, ".json") {
+		switch strings.ToLower(filepath.Ext(name)) {
+		case ".json", ".yaml", ".yml":
+		default:
 			continue
 		}
 
 		// Try to read metadata
-		data, err := os.ReadFile(name)
-		if err != nil {
+		data, err := os.ReadFile(filepath.Join(configDir(), name))
+		if err != nil || !isProfileFile(name, data) {
 			continue
 		}
 
-		var profile ConfigProfile
-		err = json.Unmarshal(data, &profile)
+		profile, _, err := decodeProfile(data)
 		if err != nil {
 			profiles = append(profiles, profileItem{
 				filename:    name,
-				description: "Invalid profile",
+				description: "Cannot import: " + err.Error(),
 				date:        "",
 			})
 			continue
@@ -1340,76 +1625,6 @@ func scanForProfiles() []profileItem {
 	return profiles
 }
 
-// exportProfile exports the current configuration to a JSON file
-func (m *model) exportProfile(description, author string) error {
-	profile := ConfigProfile{
-		Metadata: ProfileMetadata{
-			Version:           "1.0",
-			SceneShiftVersion: Version,
-			ExportDate:        time.Now(),
-			Description:       description,
-			Author:            author,
-		},
-		Apps:       m.config.Apps,
-		Presets:    m.config.Presets,
-		Theme:      m.config.Theme,
-		Protection: m.config.Protection,
-		SafeToKill: m.config.SafeToKill,
-	}
-
-	data, err := json.MarshalIndent(profile, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal profile: %v", err)
-	}
-
-	filename := fmt.Sprintf("sceneshift-profile-%s.json", time.Now().Format("2006-01-02"))
-	err = os.WriteFile(filename, data, 0644)
-	if err != nil {
-		return fmt.Errorf("failed to write file: %v", err)
-	}
-
-	m.profileMessage = fmt.Sprintf("✅ Profile exported to: %s", filename)
-	return nil
-}
-
-// importProfile imports a configuration from a JSON file
-func (m *model) importProfile(filepath string, mergeMode bool) error {
-	data, err := os.ReadFile(filepath)
-	if err != nil {
-		return fmt.Errorf("failed to read file: %v", err)
-	}
-
-	var profile ConfigProfile
-	err = json.Unmarshal(data, &profile)
-	if err != nil {
-		return fmt.Errorf("invalid profile format: %v", err)
-	}
-
-	// Version check
-	if profile.Metadata.SceneShiftVersion > Version {
-		m.profileMessage = fmt.Sprintf("⚠️ Warning: Profile from newer version (%s)", profile.Metadata.SceneShiftVersion)
-	}
-
-	if mergeMode {
-		// Merge: Add to existing config
-		m.config.Apps = append(m.config.Apps, profile.Apps...)
-		m.config.Presets = append(m.config.Presets, profile.Presets...)
-		// Don't merge theme, protection, or safe-to-kill lists
-		m.profileMessage = fmt.Sprintf("✅ Merged %d apps and %d presets", len(profile.Apps), len(profile.Presets))
-	} else {
-		// Replace: Overwrite existing config
-		m.config.Apps = profile.Apps
-		m.config.Presets = profile.Presets
-		m.config.Theme = profile.Theme
-		m.config.Protection = profile.Protection
-		m.config.SafeToKill = profile.SafeToKill
-		m.profileMessage = "✅ Profile imported successfully"
-	}
-
-	m.saveConfig()
-	return nil
-}
-
 // --- Process Fetching ---
 func fetchRunningProcesses() []list.Item {
 	procs, _ := process.Processes()
@@ -1421,9 +1636,7 @@ func fetchRunningProcesses() []list.Item {
 		}
 		path, _ := p.Exe()
 		if _, exists := uniqueMap[name]; !exists {
-			friendlyName := strings.TrimSuffix(name, filepath.Ext(name))
-			friendlyName = strings.Title(friendlyName)
-			uniqueMap[name] = processItem{name: friendlyName, exe: name, path: path}
+			uniqueMap[name] = processItem{name: friendlyName(name), exe: name, path: path}
 		}
 	}
 	items := []list.Item{}
@@ -1477,6 +1690,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.procList.SetSize(msg.Width, procHeight)
 		m.themeList.SetSize(msg.Width, themeHeight)
 		m.profileList.SetSize(msg.Width, profileHeight)
+		m.followCursor()
+		if m.currentState == stateDone {
+			m.refreshLogPane()
+		}
 
 	case tea.KeyMsg:
 		// Global Quit (Context Aware)
@@ -1488,13 +1705,57 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				break
 			}
 		}
-		if isSafe && key.Matches(msg, m.keys.Quit) {
-			m.saveConfig()
-			return m, tea.Quit
+		if m.reloadConflict {
+			return m.updateReloadConflict(msg)
+		}
+		// Quit keys typed into the pack path are part of it
+		typingPath := m.currentState == stateSafelistManager && m.safelistTab == 2 && m.safelistInput.Focused()
+		if isSafe && !m.filtering && !typingPath && key.Matches(msg, m.keys.Quit) {
+			return m.saveAndQuit()
+		}
+		if key.Matches(msg, m.keys.Palette) && m.canOpenPalette() {
+			return m.startPalette()
 		}
 
 		switch m.currentState {
+		case stateConfigRecovery:
+			return m.updateConfigRecovery(msg)
+
+		case stateExecPicker:
+			return m.updateExecPicker(msg)
+
+		case stateImportReview:
+			return m.updateImportReview(msg)
+
+		case stateAppDetail:
+			return m.updateAppDetail(msg)
+
+		case stateProcessExplorer:
+			return m.updateProcessExplorer(msg)
+
+		case stateBulkAdd:
+			return m.updateBulkAdd(msg)
+
+		case statePalette:
+			return m.updatePalette(msg)
+
+		case stateRepairPaths:
+			return m.updateRepairPaths(msg)
+
+		case stateDiagnostics:
+			switch msg.String() {
+			case "enter", "esc":
+				m.currentState = stateMenu
+			case "r":
+				return m.reload("")
+			case "q", "ctrl+c":
+				return m, tea.Quit
+			}
+
 		case stateMenu:
+			if m.filtering {
+				return m.updateAppFilter(msg)
+			}
 			for _, preset := range m.config.Presets {
 				if msg.String() == preset.Key {
 					m.applyPreset(preset)
@@ -1504,27 +1765,36 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			switch {
 			case key.Matches(msg, m.keys.Up):
-				if m.cursor > 0 {
-					m.cursor--
-				}
+				m.moveCursor(-1)
+				m.followCursor()
 			case key.Matches(msg, m.keys.Down):
-				if m.cursor < len(m.config.Apps)-1 {
-					m.cursor++
-				}
+				m.moveCursor(1)
+				m.followCursor()
 			case key.Matches(msg, m.keys.Toggle):
-				if len(m.config.Apps) > 0 {
+				if m.cursorVisible() {
 					m.config.Apps[m.cursor].Selected = !m.config.Apps[m.cursor].Selected
+					m.trigger = "manual"
 				}
 			case key.Matches(msg, m.keys.SelectAll):
-				for i := range m.config.Apps {
-					m.config.Apps[i].Selected = true
-				}
+				m.setVisibleSelected(true)
 			case key.Matches(msg, m.keys.DeselectAll):
-				for i := range m.config.Apps {
-					m.config.Apps[i].Selected = false
-				}
+				m.setVisibleSelected(false)
 			case key.Matches(msg, m.keys.Help):
 				m.help.ShowAll = !m.help.ShowAll
+				m.followCursor()
+			case key.Matches(msg, m.keys.Sort):
+				m.cycleSort()
+				m.followCursor()
+			case key.Matches(msg, m.keys.SortReverse):
+				if m.sortColumn != colNone {
+					m.sortDesc = !m.sortDesc
+					m.followCursor()
+				}
+			case key.Matches(msg, m.keys.Filter):
+				return m, m.startAppFilter()
+			case msg.String() == "esc" && m.filterInput.Value() != "":
+				m.filterInput.SetValue("")
+				m.followCursor()
 			case key.Matches(msg, m.keys.ThemeMenu):
 				m.currentState = stateThemePicker
 				return m, nil
@@ -1536,14 +1806,15 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case key.Matches(msg, m.keys.SafelistMenu):
 				m.currentState = stateSafelistManager
 				m.safelistCursor = 0
+				m.safelistTab = 0
+				m.safelistMessage = ""
+				m.setupSafelistInput()
 				return m, nil
 
 			case key.Matches(msg, m.keys.DeleteItem):
-				if len(m.config.Apps) > 0 {
-					m.config.Apps = append(m.config.Apps[:m.cursor], m.config.Apps[m.cursor+1:]...)
-					if m.cursor >= len(m.config.Apps) && m.cursor > 0 {
-						m.cursor--
-					}
+				if m.cursorVisible() {
+					m.deleteCursorApp()
+					m.followCursor()
 					m.saveConfig()
 				}
 			case key.Matches(msg, m.keys.NewItem):
@@ -1553,7 +1824,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.currentState = stateAppEdit
 				return m, nil
 			case key.Matches(msg, m.keys.EditItem):
-				if len(m.config.Apps) == 0 {
+				if !m.cursorVisible() {
 					return m, nil
 				}
 				m.isNewItem = false
@@ -1563,10 +1834,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.inputs[0].SetValue(app.Name)
 				m.inputs[1].SetValue(app.ProcessName)
 				m.inputs[2].SetValue(app.ExecPath)
+				m.inputs[3].SetValue(joinArgs(app.Args))
 				m.currentState = stateAppEdit
 				return m, nil
 
-			case msg.String() == "h":
+			case key.Matches(msg, m.keys.History):
 				if m.history.IsEmpty() {
 					return m, nil
 				}
@@ -1574,18 +1846,22 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.historyCursor = len(m.history.Entries) - 1
 				return m, nil
 
-			case msg.String() == "u", msg.String() == "ctrl+z":
+			case key.Matches(msg, m.keys.Undo):
 				return m, m.performUndo()
 
-			case msg.String() == "ctrl+e":
-				// Export profile
-				m.currentState = stateProfileExport
-				m.profileDescription = ""
-				m.profileAuthor = ""
-				m.setupProfileInputs()
-				return m, nil
+			case key.Matches(msg, m.keys.Export):
+				return m.startProfileExport()
 
-			case msg.String() == "i":
+			case key.Matches(msg, m.keys.RepairPaths):
+				return m.startPathRepair()
+
+			case key.Matches(msg, m.keys.Details):
+				return m.startAppDetail()
+
+			case key.Matches(msg, m.keys.Explorer):
+				return m.startProcessExplorer()
+
+			case key.Matches(msg, m.keys.Import):
 				// Import profile - scan for available profiles
 				profiles := scanForProfiles()
 
@@ -1597,64 +1873,51 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.profileList = list.New(items, list.NewDefaultDelegate(), 0, 0)
 				m.profileList.Title = "Select Profile to Import"
 				m.profileList.SetShowHelp(false)
+				plainList(&m.profileList)
 
 				m.currentState = stateProfileImport
 				return m, nil
 
 			case key.Matches(msg, m.keys.Kill):
+				if !m.allowAction("kill") {
+					return m, nil
+				}
 				m.mode = "kill"
 				m.currentState = stateCountdown
-				m.countdown = 5
-				return m, tickCmd()
+				return m, m.startCountdown()
 			case key.Matches(msg, m.keys.Restore):
+				if !m.allowAction("restore") {
+					return m, nil
+				}
 				m.mode = "restore"
 				m.currentState = stateCountdown
-				m.countdown = 5
-				m.progress = progress.New(
+				m.progress = progress.New(progressOptions(
 					progress.WithGradient(m.config.Theme.Restore, m.config.Theme.Highlight),
 					progress.WithWidth(40),
-				)
-				return m, tickCmd()
+				)...)
+				return m, m.startCountdown()
 			case key.Matches(msg, m.keys.Suspend):
+				if !m.allowAction("suspend") {
+					return m, nil
+				}
 				m.mode = "suspend"
 				m.currentState = stateCountdown
-				m.countdown = 5
-				m.progress = progress.New(
+				m.progress = progress.New(progressOptions(
 					progress.WithGradient(m.config.Theme.Suspend, m.config.Theme.Highlight),
 					progress.WithWidth(40),
-				)
-				return m, tickCmd()
+				)...)
+				return m, m.startCountdown()
 			case key.Matches(msg, m.keys.Resume):
+				if !m.allowAction("resume") {
+					return m, nil
+				}
 				m.mode = "resume"
 				m.currentState = stateCountdown
-				m.countdown = 5
-				m.progress = progress.New(
+				m.progress = progress.New(progressOptions(
 					progress.WithGradient(m.config.Theme.Restore, m.config.Theme.Highlight),
 					progress.WithWidth(40),
-				)
-				return m, tickCmd()
-			case key.Matches(msg, m.keys.SafelistMenu):
-				m.currentState = stateSafelistManager
-				m.safelistCursor = 0
-				return m, nil
-
-			// NEW: History keybindings
-			case msg.String() == "h":
-				if m.history.IsEmpty() {
-					// Show a message or do nothing
-					return m, nil
-				}
-				m.currentState = stateHistory
-				m.historyCursor = len(m.history.Entries) - 1
-				return m, nil
-
-			case msg.String() == "u":
-				return m, m.performUndo()
-
-			case msg.String() == "ctrl+z":
-				return m, m.performUndo()
-				// END NEW
-
+				)...)
+				return m, m.startCountdown()
 			}
 
 		case statePresetList:
@@ -1737,11 +2000,23 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					Key:  m.inputs[1].Value(),
 					Apps: cleanApps,
 				}
-
+				if owner := m.keys.boundTo(newPreset.Key); newPreset.Key != "" && owner != "" {
+					m.presetMessage = fmt.Sprintf(glyphs("❌ Key %q is already bound to %s"), newPreset.Key, owner)
+					return m, nil
+				}
+				for i, p := range m.config.Presets {
+					if p.Key == newPreset.Key && newPreset.Key != "" && (m.isNewItem || i != m.presetCursor) {
+						m.presetMessage = fmt.Sprintf(glyphs("❌ Key %q is already used by preset %s"), newPreset.Key, p.Name)
+						return m, nil
+					}
+				}
+				m.presetMessage = ""
+
 				if m.isNewItem {
 					m.config.Presets = append(m.config.Presets, newPreset)
 					m.presetCursor = len(m.config.Presets) - 1
 				} else {
+					newPreset.Source = m.config.Presets[m.presetCursor].Source
 					m.config.Presets[m.presetCursor] = newPreset
 				}
 				m.saveConfig()
@@ -1816,7 +2091,23 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case stateSafelistManager:
 			var cmd tea.Cmd
 			switch {
-			case key.Matches(msg, m.keys.Quit), msg.String() == "esc":
+			case msg.String() == "esc":
+				m.currentState = stateMenu
+				return m, nil
+			case msg.String() == "tab", msg.String() == "shift+tab":
+				if msg.String() == "tab" {
+					m.safelistTab = (m.safelistTab + 1) % 3
+				} else {
+					m.safelistTab = (m.safelistTab + 2) % 3
+				}
+				m.safelistCursor = 0
+				m.safelistMessage = ""
+				m.safelistInput.SetValue("")
+				m.setupSafelistInput()
+				return m, nil
+			case m.safelistTab == 2:
+				return m.updatePackManager(msg)
+			case key.Matches(msg, m.keys.Quit):
 				m.currentState = stateMenu
 				return m, nil
 			case key.Matches(msg, m.keys.Up):
@@ -1824,16 +2115,25 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.safelistCursor--
 				}
 			case key.Matches(msg, m.keys.Down):
-				if m.safelistCursor < len(m.config.Protection.ExclusionList)-1 {
+				if m.safelistCursor < m.safelistLen()-1 {
 					m.safelistCursor++
 				}
+			case m.safelistTab == 1:
+				// Safe-to-kill entries are read-only here; edit config.yaml or packs
+				return m, nil
 			case key.Matches(msg, m.keys.DeleteItem):
-				if len(m.config.Protection.ExclusionList) > 0 {
+				rows := m.config.exclusionRows()
+				if len(rows) > 0 {
+					row := rows[m.safelistCursor]
+					if row.locked {
+						m.safelistMessage = fmt.Sprintf(glyphs("🔒 %s is locked by system policy"), row.name)
+						return m, nil
+					}
 					m.config.Protection.ExclusionList = append(
-						m.config.Protection.ExclusionList[:m.safelistCursor],
-						m.config.Protection.ExclusionList[m.safelistCursor+1:]...,
+						m.config.Protection.ExclusionList[:row.index],
+						m.config.Protection.ExclusionList[row.index+1:]...,
 					)
-					if m.safelistCursor >= len(m.config.Protection.ExclusionList) && m.safelistCursor > 0 {
+					if m.safelistCursor >= len(rows)-1 && m.safelistCursor > 0 {
 						m.safelistCursor--
 					}
 					m.saveConfig()
@@ -1852,27 +2152,27 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case stateHistory:
-			switch msg.String() {
-			case "up", "k":
+			switch {
+			case key.Matches(msg, m.keys.Up):
 				if m.historyCursor < len(m.history.Entries)-1 {
 					m.historyCursor++
 				}
 				return m, nil
 
-			case "down", "j":
+			case key.Matches(msg, m.keys.Down):
 				if m.historyCursor > 0 {
 					m.historyCursor--
 				}
 				return m, nil
 
-			case "u":
+			case key.Matches(msg, m.keys.Undo):
 				// Undo last operation (most recent)
 				if !m.history.IsEmpty() {
 					return m, m.performUndo()
 				}
 				return m, nil
 
-			case "esc":
+			case msg.String() == "esc":
 				m.currentState = stateMenu
 				return m, nil
 			}
@@ -1889,60 +2189,14 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		case stateProfileExport:
-			switch msg.String() {
-			case "enter":
-				description := m.inputs[0].Value()
-				author := m.inputs[1].Value()
-
-				if description == "" {
-					description = "SceneShift configuration"
-				}
-
-				err := m.exportProfile(description, author)
-				if err != nil {
-					m.profileMessage = fmt.Sprintf("❌ Export failed: %v", err)
-				}
-
-				m.currentState = stateMenu
-				return m, nil
-
-			case "esc":
-				m.currentState = stateMenu
-				return m, nil
-
-			case "tab", "shift+tab":
-				if msg.String() == "tab" {
-					m.focusIndex = (m.focusIndex + 1) % len(m.inputs)
-				} else {
-					m.focusIndex = (m.focusIndex - 1 + len(m.inputs)) % len(m.inputs)
-				}
-			}
-
-			// Update inputs
-			cmds := make([]tea.Cmd, len(m.inputs))
-			for i := range m.inputs {
-				if i == m.focusIndex {
-					cmdFocus := m.inputs[i].Focus()
-					m.inputs[i], cmd = m.inputs[i].Update(msg)
-					cmds[i] = tea.Batch(cmdFocus, cmd)
-				} else {
-					m.inputs[i].Blur()
-				}
-			}
-			return m, tea.Batch(cmds...)
+			return m.updateProfileExport(msg)
 
 		case stateProfileImport:
 			switch msg.String() {
 			case "enter":
 				if m.profileList.SelectedItem() != nil {
 					filename := m.profileList.SelectedItem().(profileItem).filename
-
-					err := m.importProfile(filename, true)
-					if err != nil {
-						m.profileMessage = fmt.Sprintf("❌ Import failed: %v", err)
-					}
-
-					m.currentState = stateMenu
+					return m.startImportReview(filename)
 				}
 				return m, nil
 
@@ -1956,9 +2210,13 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 
 		case stateAppEdit:
+			if key.Matches(msg, m.keys.FindExec) {
+				return m.startExecDiscovery()
+			}
 			if key.Matches(msg, m.keys.SearchProc) {
 				m.currentState = stateProcessPicker
 				m.allProcs = fetchRunningProcesses()
+				m.pickMarked = map[string]bool{}
 				m.searchInput.SetValue("")
 				m.procList.SetItems(m.allProcs)
 				m.procList.ResetSelected()
@@ -1971,6 +2229,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					Name:        m.inputs[0].Value(),
 					ProcessName: m.inputs[1].Value(),
 					ExecPath:    m.inputs[2].Value(),
+					Args:        splitArgs(m.inputs[3].Value()),
 					Selected:    true,
 					SafetyLevel: detectSafetyLevel(m.inputs[1].Value(), &m.config), // NEW
 				}
@@ -1980,6 +2239,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				} else {
 					currSel := m.config.Apps[m.cursor].Selected
 					newApp.Selected = currSel
+					newApp.Sources = m.config.Apps[m.cursor].Sources
 					m.config.Apps[m.cursor] = newApp
 				}
 				m.saveConfig()
@@ -2012,13 +2272,26 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case stateProcessPicker:
 			switch msg.String() {
+			case "tab":
+				// Several processes can only be added at once as new apps
+				if m.isNewItem {
+					m.togglePickerMark()
+				}
+				return m, nil
 			case "enter":
+				if len(m.pickMarked) > 0 {
+					return m.startBulkAdd()
+				}
 				if m.procList.SelectedItem() != nil {
 					i, ok := m.procList.SelectedItem().(processItem)
 					if ok {
 						m.inputs[0].SetValue(i.name)
 						m.inputs[1].SetValue(i.exe)
 						m.inputs[2].SetValue(i.path)
+						if i.path == "" {
+							// Path hidden, e.g. an elevated process; look for the install
+							return m.startExecDiscovery()
+						}
 					}
 					m.currentState = stateAppEdit
 					return m, nil
@@ -2112,21 +2385,35 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, tea.Batch(cmds...)
 
-		case stateDone:
-			if key.Matches(msg, m.keys.Quit) {
-				m.saveConfig()
-				return m, tea.Quit
+		case stateCountdown:
+			if msg.String() == "esc" || key.Matches(msg, m.keys.Quit) {
+				return m.cancelCountdown()
 			}
-			m.currentState = stateMenu
-			m.logs = []string{}
-			m.progPercent = 0
+
+		case stateDone:
+			return m.updateDone(msg)
 		}
 
+	case discoveryMsg:
+		return m.applyDiscovery(msg)
+
+	case configWatchMsg:
+		return m.updateConfigWatch()
+
+	case statsTickMsg, statsMsg:
+		return m.updateStats(msg)
+
+	case paletteProcsMsg:
+		return m.updatePaletteProcs(msg)
+
+	case tea.MouseMsg:
+		return m.updateMouse(msg)
+
 	case tickMsg:
-		if m.currentState == stateCountdown {
+		if m.currentState == stateCountdown && msg.id == m.countdownID {
 			if m.countdown > 0 {
 				m.countdown--
-				return m, tickCmd()
+				return m, tickCmd(m.countdownID)
 			}
 			m.currentState = stateProcessing
 			m.startRAM = getRAMUsageMB()
@@ -2141,7 +2428,14 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 
 	case processResultMsg:
+		m.noteAuditErr(msg.audit)
 		m.logs = append(m.logs, msg.message)
+		if msg.detail != nil {
+			if m.logDetails == nil {
+				m.logDetails = map[int]logDetail{}
+			}
+			m.logDetails[len(m.logs)-1] = *msg.detail
+		}
 		m.progPercent = msg.percent
 		cmd := m.progress.SetPercent(msg.percent)
 
@@ -2185,13 +2479,14 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				endRAM := getRAMUsageMB()
 				if m.startRAM > endRAM {
 					freed := m.startRAM - endRAM
-					statMsg := fmt.Sprintf("🚀 RAM Reclaimed: %d MB", freed)
+					statMsg := fmt.Sprintf(glyphs("🚀 RAM Reclaimed: %d MB"), freed)
 					m.logs = append(m.logs, statMsg)
 				} else {
-					m.logs = append(m.logs, "✨ Process cleanup complete.")
+					m.logs = append(m.logs, glyphs("✨ Process cleanup complete."))
 				}
 			}
 			m.currentState = stateDone
+			m.openLog()
 			return m, cmd
 		}
 		return m, tea.Batch(cmd, waitForNextProcess(m, msg.index+1))
@@ -2202,8 +2497,52 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // --- Helpers ---
 
+// updateConfigRecovery handles the screen shown when config.yaml fails to
+// parse. Hotkeys come from the broken config, so only fixed keys are used.
+func (m model) updateConfigRecovery(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if m.backupCursor > 0 {
+			m.backupCursor--
+		}
+	case "down", "j":
+		if m.backupCursor < len(m.backups)-1 {
+			m.backupCursor++
+		}
+	case "enter":
+		if len(m.backups) == 0 {
+			return m, nil
+		}
+		if err := restoreConfigBackup(m.backups[m.backupCursor]); err != nil {
+			m.statusMessage = glyphs("❌ ") + err.Error()
+			return m, nil
+		}
+		return m.reload(glyphs("✅ Restored ") + filepath.Base(m.backups[m.backupCursor]))
+	case "n":
+		if err := setAsideBrokenConfig(); err != nil {
+			m.statusMessage = glyphs("❌ ") + err.Error()
+			return m, nil
+		}
+		if _, err := createDefaultConfig(); err != nil {
+			m.statusMessage = glyphs("❌ ") + err.Error()
+			return m, nil
+		}
+		return m.reload(glyphs("✅ Started with a fresh config; the broken file was kept next to it"))
+	case "q", "ctrl+c", "esc":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// reload rebuilds the model from the config files on disk
+func (m model) reload(status string) (tea.Model, tea.Cmd) {
+	nm := initialModel()
+	nm.statusMessage = status
+	return nm.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+}
+
 func (m *model) setupAppInputs() {
-	m.inputs = make([]textinput.Model, 3)
+	m.inputs = make([]textinput.Model, 4)
 	for i := range m.inputs {
 		t := textinput.New()
 		t.Cursor.Style = lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Highlight))
@@ -2212,9 +2551,13 @@ func (m *model) setupAppInputs() {
 	m.inputs[0].Prompt = "Name: "
 	m.inputs[1].Prompt = "Process: "
 	m.inputs[2].Prompt = "Path: "
+	m.inputs[2].Placeholder = `${LOCALAPPDATA}\App\App.exe`
+	m.inputs[3].Prompt = "Args: "
+	m.inputs[3].Placeholder = "Optional launch arguments"
 }
 
 func (m *model) setupPresetInputs() {
+	m.presetMessage = ""
 	m.inputs = make([]textinput.Model, 3)
 	for i := range m.inputs {
 		t := textinput.New()
@@ -2249,7 +2592,7 @@ func (m *model) setupThemeInputs() {
 
 func (m *model) setupProfileInputs() {
 	if m.currentState == stateProfileExport {
-		m.inputs = make([]textinput.Model, 2)
+		m.inputs = make([]textinput.Model, 3)
 		for i := range m.inputs {
 			t := textinput.New()
 			t.Cursor.Style = lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Highlight))
@@ -2259,6 +2602,8 @@ func (m *model) setupProfileInputs() {
 		m.inputs[0].Placeholder = "My gaming optimization setup"
 		m.inputs[1].Prompt = "Author (optional): "
 		m.inputs[1].Placeholder = "Your name"
+		m.inputs[2].Prompt = "File: "
+		m.inputs[2].Placeholder = "streaming-scene.yaml"
 		m.focusIndex = 0
 		m.inputs[0].Focus()
 	} else if m.currentState == stateProfileImport {
@@ -2272,7 +2617,114 @@ func (m *model) setupProfileInputs() {
 	}
 }
 
+// safelistLen returns the number of rows in the active safelist manager tab
+func (m *model) safelistLen() int {
+	switch m.safelistTab {
+	case 1:
+		return len(m.config.safeToKillEntries())
+	case 2:
+		return len(m.config.Packs)
+	default:
+		return len(m.config.exclusionRows())
+	}
+}
+
+func (m *model) setupSafelistInput() {
+	if m.safelistTab == 2 {
+		// Focused on demand, so the list keys work until a path is typed
+		m.safelistInput.Prompt = glyphs("📦 Import pack: ")
+		m.safelistInput.Placeholder = `C:\path\to\pack.yaml`
+		m.safelistInput.Blur()
+	} else {
+		m.safelistInput.Prompt = glyphs("➕ Add: ")
+		m.safelistInput.Placeholder = "processname.exe"
+		m.safelistInput.Focus()
+	}
+}
+
+// updatePackManager handles keys on the packs tab of the safelist manager.
+// While the import path input is focused every key goes to it, so paths
+// containing spaces or action keys can be typed; Enter imports the path, or
+// returns to the list if it is empty.
+func (m model) updatePackManager(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.safelistInput.Focused() {
+		if msg.String() != "enter" {
+			var cmd tea.Cmd
+			m.safelistInput, cmd = m.safelistInput.Update(msg)
+			return m, cmd
+		}
+		path := strings.TrimSpace(m.safelistInput.Value())
+		if path == "" {
+			m.safelistInput.Blur()
+			return m, nil
+		}
+		ref, err := importPack(&m.config, path)
+		if err != nil {
+			m.safelistMessage = fmt.Sprintf(glyphs("❌ Import failed: %v"), err)
+			return m, nil
+		}
+		m.safelistMessage = fmt.Sprintf(glyphs("✅ Imported %s v%s (%d entries)"), ref.Name, ref.Version, ref.Count)
+		m.safelistInput.SetValue("")
+		m.safelistInput.Blur()
+		m.saveConfig()
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		m.currentState = stateMenu
+	case key.Matches(msg, m.keys.Up):
+		if m.safelistCursor > 0 {
+			m.safelistCursor--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.safelistCursor < m.safelistLen()-1 {
+			m.safelistCursor++
+		}
+	case key.Matches(msg, m.keys.Toggle):
+		if len(m.config.Packs) > 0 {
+			ref := &m.config.Packs[m.safelistCursor]
+			ref.Enabled = !ref.Enabled
+			loadPacks(&m.config)
+			m.saveConfig()
+		}
+	case key.Matches(msg, m.keys.DeleteItem):
+		if len(m.config.Packs) > 0 {
+			name := m.config.Packs[m.safelistCursor].Name
+			removePack(&m.config, m.safelistCursor)
+			if m.safelistCursor >= len(m.config.Packs) && m.safelistCursor > 0 {
+				m.safelistCursor--
+			}
+			m.safelistMessage = fmt.Sprintf(glyphs("🗑️ Removed pack %s"), name)
+			m.saveConfig()
+		}
+	case key.Matches(msg, m.keys.NewItem), msg.String() == "enter":
+		m.safelistMessage = ""
+		return m, m.safelistInput.Focus()
+	}
+	return m, nil
+}
+
+// allowAction checks the system policy before starting a countdown and
+// reports a refusal on the main menu
+func (m *model) allowAction(mode string) bool {
+	targets := 0
+	for _, app := range m.config.Apps {
+		if app.Selected {
+			targets++
+		}
+	}
+	if err := m.config.Policy.CheckAction(mode, targets); err != nil {
+		m.statusMessage = glyphs("🔒 ") + err.Error()
+		m.logAudit(AuditRecord{Action: mode, Result: "denied", Error: err.Error(), Trigger: m.trigger})
+		return false
+	}
+	m.statusMessage = ""
+	return true
+}
+
 func (m *model) applyPreset(p PresetConfig) {
+	m.trigger = "preset:" + p.Name
 	for i := range m.config.Apps {
 		m.config.Apps[i].Selected = false
 	}
@@ -2287,14 +2739,24 @@ func (m *model) applyPreset(p PresetConfig) {
 
 // --- Commands ---
 
-func tickCmd() tea.Cmd {
-	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
-		return tickMsg(t)
+func tickCmd(id int) tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return tickMsg{id: id}
 	})
 }
 
+// startCountdown starts the countdown to an action. Each countdown has its
+// own id, so the ticks of one that was cancelled cannot speed up the next.
+func (m *model) startCountdown() tea.Cmd {
+	m.countdownID++
+	m.countdown = 5
+	return tickCmd(m.countdownID)
+}
+
 type processResultMsg struct {
 	message string
+	detail  *logDetail // What the app matched and why it failed, if acted on
+	audit   error      // Failure to write the app's audit record
 	percent float64
 	done    bool
 	index   int
@@ -2312,7 +2774,7 @@ func waitForNextProcess(m model, index int) tea.Cmd {
 			return processResultMsg{message: "All tasks completed.", percent: 1.0, done: true, index: index}
 		}
 
-		app := m.config.Apps[index]
+		app := m.config.resolveApp(m.config.Apps[index])
 		percent := float64(index+1) / float64(len(m.config.Apps))
 
 		if !app.Selected {
@@ -2324,10 +2786,28 @@ func waitForNextProcess(m model, index int) tea.Cmd {
 			}
 		}
 
-		// Check exclusion list (protected processes)
-		if isInSafelist(app.ProcessName, m.config.Protection.ExclusionList) {
+		record := AuditRecord{Action: m.mode, App: app.Name, Process: app.ProcessName, Trigger: m.trigger}
+
+		// Re-check the system policy in case it was bypassed by the caller
+		if m.config.Policy.Forbids(m.mode) {
+			record.Result, record.Error = "blocked", "action disabled by system policy"
 			return processResultMsg{
-				message: fmt.Sprintf("[🛡️ PROTECTED] %s cannot be modified", app.Name),
+				detail:  &logDetail{err: errors.New(record.Error)},
+				audit:   m.audit.Log(record),
+				message: fmt.Sprintf(glyphs("[🔒 POLICY] %s: %s is disabled by system policy"), app.Name, strings.ToUpper(m.mode)),
+				percent: percent,
+				done:    false,
+				index:   index,
+			}
+		}
+
+		// Check exclusion list and policy (protected processes)
+		if m.config.isProtected(app.ProcessName) {
+			record.Result, record.Error = "blocked", "protected process"
+			return processResultMsg{
+				detail:  &logDetail{err: errors.New(record.Error)},
+				audit:   m.audit.Log(record),
+				message: fmt.Sprintf(glyphs("[🛡️ PROTECTED] %s cannot be modified"), app.Name),
 				percent: percent,
 				done:    false,
 				index:   index,
@@ -2335,9 +2815,12 @@ func waitForNextProcess(m model, index int) tea.Cmd {
 		}
 
 		var msg string
+		var err error
 		switch m.mode {
 		case "kill":
-			err := killProcess(app.ProcessName)
+			record.Targets = auditTargetsByName(app.ProcessName)
+			err = killProcess(app.ProcessName)
+			record.Result, record.Error = auditResult(err)
 			if err != nil {
 				msg = fmt.Sprintf("[ERR]  %s: %v", app.Name, err)
 				failedCount++
@@ -2348,7 +2831,9 @@ func waitForNextProcess(m model, index int) tea.Cmd {
 		case "suspend":
 			// Get reference to the actual app in config
 			appRef := &m.config.Apps[index]
-			err := suspendProcessByName(appRef.ProcessName, appRef)
+			record.Targets = auditTargetsByName(app.ProcessName)
+			err = suspendProcessByName(app.ProcessName, appRef)
+			record.Result, record.Error = auditResult(err)
 			if err != nil {
 				msg = fmt.Sprintf("[ERR]  %s: %v", app.Name, err)
 				failedCount++
@@ -2359,7 +2844,13 @@ func waitForNextProcess(m model, index int) tea.Cmd {
 		case "resume":
 			// Get reference to the actual app in config
 			appRef := &m.config.Apps[index]
-			err := resumeProcessByName(appRef)
+			pids := make([]int32, 0, len(appRef.PIDs))
+			for pid := range appRef.PIDs {
+				pids = append(pids, pid)
+			}
+			record.Targets = auditTargetsByPID(pids)
+			err = resumeProcessByName(appRef, app.ExecPath)
+			record.Result, record.Error = auditResult(err)
 			if err != nil {
 				msg = fmt.Sprintf("[ERR]  %s: %v", app.Name, err)
 				failedCount++
@@ -2370,19 +2861,31 @@ func waitForNextProcess(m model, index int) tea.Cmd {
 		case "restore":
 			if app.ExecPath == "" {
 				msg = fmt.Sprintf("[SKIP] %s: no path", app.Name)
+				err = errors.New("no executable path")
+				record.Result, record.Error = auditResult(err)
 				failedCount++
 			} else {
-				cmd := exec.Command(app.ExecPath)
-				if err := cmd.Start(); err != nil {
+				cmd := launchCommand(app)
+				err = cmd.Start()
+				record.Result, record.Error = auditResult(err)
+				if err != nil {
 					msg = fmt.Sprintf("[ERR]  %s: %v", app.Name, err)
 					failedCount++
 				} else {
+					record.Targets = []AuditTarget{{PID: int32(cmd.Process.Pid), Exe: app.ExecPath}}
 					msg = fmt.Sprintf("[REST] Launched %s", app.Name)
 					successCount++
 				}
 			}
 		}
-		return processResultMsg{message: msg, percent: percent, done: false, index: index}
+		return processResultMsg{
+			message: msg,
+			detail:  &logDetail{targets: record.Targets, err: err},
+			audit:   m.audit.Log(record),
+			percent: percent,
+			done:    false,
+			index:   index,
+		}
 	}
 }
 
@@ -2429,7 +2932,36 @@ func suspendProcessByName(rawNames string, app *AppEntry) error {
 	return fmt.Errorf("no processes found")
 }
 
-func resumeProcessByName(app *AppEntry) error {
+// suspendPIDs suspends the given processes of app and records them
+func suspendPIDs(pids []int32, app *AppEntry) error {
+	if app.PIDs == nil {
+		app.PIDs = make(map[int32]bool)
+	}
+	var lastErr error
+	suspendedCount := 0
+	for _, pid := range pids {
+		if !pidExists(pid) {
+			continue
+		}
+		if err := suspendProcess(pid); err != nil {
+			lastErr = err
+		} else {
+			app.PIDs[pid] = true
+			suspendedCount++
+		}
+	}
+	if suspendedCount > 0 {
+		return nil
+	}
+	if lastErr != nil {
+		return lastErr
+	}
+	return fmt.Errorf("no processes found")
+}
+
+// resumeProcessByName resumes the PIDs suspended for app. execPath is the
+// expanded exec_path, used to check a PID was not reused by another program.
+func resumeProcessByName(app *AppEntry, execPath string) error {
 	// No PIDs tracked = nothing to resume
 	if len(app.PIDs) == 0 {
 		return fmt.Errorf("no suspended processes found for %s", app.Name)
@@ -2447,11 +2979,11 @@ func resumeProcessByName(app *AppEntry) error {
 		}
 
 		// Optional: Validate executable path matches
-		if app.ExecPath != "" {
+		if execPath != "" {
 			p, err := process.NewProcess(pid)
 			if err == nil {
 				if exe, err := p.Exe(); err == nil {
-					if !strings.EqualFold(exe, app.ExecPath) {
+					if !strings.EqualFold(exe, execPath) {
 						invalidPIDs = append(invalidPIDs, pid)
 						continue
 					}
@@ -2534,7 +3066,6 @@ func startProcess(rawNames, path string) error {
 
 func (m model) View() string {
 	base := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Text))
-	presetStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Warn)).Italic(true).MarginTop(1)
 	selected := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Select)).Bold(true)
 	unselected := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Text)).Faint(true)
 	killStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Kill)).Bold(true)
@@ -2542,81 +3073,92 @@ func (m model) View() string {
 	inputStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Highlight)).MarginBottom(1)
 	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Select)).Bold(true).Underline(true)
 
+	if m.reloadConflict {
+		return display.render(lipgloss.NewStyle().Padding(viewPadTop, viewPadLeft).Render(m.viewReloadConflict()))
+	}
+
 	var s string
 
 	switch m.currentState {
-	case stateMenu:
-		logoStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Kill)).Bold(true).MarginBottom(1)
-		s += logoStyle.Render(logoASCII) + "\n"
-		s += lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Text)).Faint(true).Render("  by tandukuda") + "\n\n"
+	case stateConfigRecovery:
+		warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Warn))
+		s += titleStyle.Render(glyphs("⚠️  CONFIG COULD NOT BE LOADED")) + "\n\n"
+		s += killStyle.Render(fmt.Sprintf("%v", m.configLoadErr)) + "\n\n"
+		s += base.Render("Changes will not be saved until this is resolved.") + "\n\n"
 
-		if len(m.config.Apps) == 0 {
-			s += lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Warn)).Render("No apps configured. Press 'n' to add one.") + "\n"
+		if len(m.backups) == 0 {
+			s += warnStyle.Render("No backups found in "+backupsPath()) + "\n"
 		} else {
-			for i, app := range m.config.Apps {
+			s += base.Render("Restore a backup (newest first):") + "\n\n"
+			for i, b := range m.backups {
 				cursor := "  "
-				if m.cursor == i {
+				if m.backupCursor == i {
 					cursor = "> "
 				}
-				check := "[ ]"
-				if app.Selected {
-					check = "[x]"
-				}
-
-				// Safety indicator
-				safetyIcon := ""
-				switch app.SafetyLevel {
-				case "protected":
-					safetyIcon = "🛡️ "
-				case "safe":
-					safetyIcon = "✓ "
-				case "caution":
-					safetyIcon = "⚠ "
-				default:
-					safetyIcon = "  "
-				}
-
-				// NEW: Status indicator and stats
-				status := getProcessStatus(app)
-				statusIcon := getStatusIcon(status)
-
-				// Get stats from cache
-				stats := m.statsCache.Get(app.ProcessName)
-				statsStr := ""
-				if stats.IsRunning {
-					statsStr = fmt.Sprintf(" CPU: %.1f%% RAM: %d MB", stats.CPUPercent, stats.RAMMB)
+				label := cursor + filepath.Base(b)
+				if i == 0 {
+					label += " (latest)"
 				}
-
-				label := fmt.Sprintf("%s %s %s%s %s%s", cursor, check, safetyIcon, app.Name, statusIcon, statsStr)
-
-				if m.cursor == i {
+				if m.backupCursor == i {
 					s += selected.Render(label) + "\n"
 				} else {
 					s += unselected.Render(label) + "\n"
 				}
 			}
 		}
-
-		var presetHints []string
-		for _, p := range m.config.Presets {
-			presetHints = append(presetHints, fmt.Sprintf("[%s] %s", p.Key, p.Name))
-		}
-		if len(presetHints) > 0 {
-			s += presetStyle.Render("Presets: "+strings.Join(presetHints, "  ")) + "\n"
+		if m.statusMessage != "" {
+			s += "\n" + warnStyle.Render(m.statusMessage) + "\n"
 		}
-		s += "\n" + lipgloss.NewStyle().Faint(true).Render("Legend: 🛡️=Protected  ✓=Safe  ⚠=Caution  |  ▶️=Running  ⏸️=Suspended") + "\n"
+		s += "\n" + lipgloss.NewStyle().Faint(true).Render(glyphs("Enter: Restore backup • n: Start fresh (keeps broken file) • q: Quit and fix by hand")) + "\n"
+
+	case stateExecPicker:
+		s += m.viewExecPicker()
+
+	case stateImportReview:
+		s += m.viewImportReview()
+
+	case stateAppDetail:
+		s += m.viewAppDetail()
+
+	case stateProcessExplorer:
+		s += m.viewProcessExplorer()
 
-		// Show profile message if present
-		if m.profileMessage != "" {
-			s += "\n" + lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Highlight)).Render(m.profileMessage) + "\n"
+	case stateBulkAdd:
+		s += m.viewBulkAdd()
+
+	case statePalette:
+		s += m.viewPalette()
+
+	case stateRepairPaths:
+		s += m.viewRepairPaths()
+
+	case stateDiagnostics:
+		warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Warn))
+		s += titleStyle.Render(glyphs("🩺 CONFIG DIAGNOSTICS")) + "\n\n"
+		if len(m.diagnostics) == 0 {
+			s += restoreStyle.Render(glyphs("✅ No problems found")) + "\n"
+		}
+		for _, d := range m.diagnostics {
+			location := filepath.Base(d.File)
+			if d.Line > 0 {
+				location = fmt.Sprintf("%s:%d", location, d.Line)
+			}
+			if d.Severity == "error" {
+				s += killStyle.Render(glyphs("✗ ")+location) + " " + base.Render(d.Message) + "\n"
+			} else {
+				s += warnStyle.Render(glyphs("⚠ ")+location) + " " + base.Render(d.Message) + "\n"
+			}
 		}
+		s += "\n" + unselected.Render("Files: "+configDir()) + "\n"
+		s += "\n" + lipgloss.NewStyle().Faint(true).Render(glyphs("Enter: Continue • r: Re-check after editing • q: Quit")) + "\n"
 
-		s += "\n" + m.help.View(m.keys)
+	case stateMenu:
+		s += m.viewMenu()
 
 	case statePresetList:
 		s += titleStyle.Render("MANAGE PRESETS") + "\n\n"
 		if len(m.config.Presets) == 0 {
-			s += lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Warn)).Render("No presets. Press 'n' to create one.") + "\n"
+			s += lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Warn)).Render("No presets. Press '"+hint(m.keys.NewItem)+"' to create one.") + "\n"
 		} else {
 			for i, p := range m.config.Presets {
 				cursor := "  "
@@ -2632,7 +3174,7 @@ func (m model) View() string {
 				}
 			}
 		}
-		s += "\n" + lipgloss.NewStyle().Faint(true).Render("n: new, e: edit, d: delete, esc: back")
+		s += "\n" + lipgloss.NewStyle().Faint(true).Render(fmt.Sprintf("%s: new, %s: edit, %s: delete, esc: back", hint(m.keys.NewItem), hint(m.keys.EditItem), hint(m.keys.DeleteItem)))
 
 	case statePresetEdit:
 		title := "EDIT PRESET"
@@ -2640,10 +3182,13 @@ func (m model) View() string {
 			title = "NEW PRESET"
 		}
 		s += titleStyle.Render(title) + "\n\n"
-		s += lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Warn)).Render("Press Ctrl+F to select apps from list!") + "\n\n"
+		s += lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Warn)).Render("Press "+hint(m.keys.SearchProc)+" to select apps from list!") + "\n\n"
 		for i := range m.inputs {
 			s += inputStyle.Render(m.inputs[i].View()) + "\n"
 		}
+		if m.presetMessage != "" {
+			s += "\n" + killStyle.Render(m.presetMessage) + "\n"
+		}
 		s += lipgloss.NewStyle().Faint(true).Render("\n(Tab to Move, Enter to Save, Esc to Cancel)")
 
 	case statePresetAppPicker:
@@ -2673,27 +3218,115 @@ func (m model) View() string {
 
 	case stateSafelistManager:
 		titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Select)).Bold(true).Underline(true)
+		warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Warn))
 		s += titleStyle.Render("EXCLUSION LIST MANAGER") + "\n\n"
-		s += lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Warn)).Render("🛡️ Protected processes that cannot be killed or suspended:") + "\n\n"
 
-		if len(m.config.Protection.ExclusionList) == 0 {
-			s += lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Warn)).Render("No processes in exclusion list.") + "\n"
-		} else {
-			for i, proc := range m.config.Protection.ExclusionList {
+		tabs := []string{"Exclusion List", "Safe to Kill", "Packs"}
+		for i, t := range tabs {
+			if i == m.safelistTab {
+				s += selected.Render("["+t+"]") + " "
+			} else {
+				s += unselected.Render(" "+t+" ") + " "
+			}
+		}
+		s += "\n\n"
+
+		switch m.safelistTab {
+		case 0:
+			s += warnStyle.Render(glyphs("🛡️ Protected processes that cannot be killed or suspended:")) + "\n\n"
+
+			rows := m.config.exclusionRows()
+			if len(rows) == 0 {
+				s += warnStyle.Render("No processes in exclusion list.") + "\n"
+			} else {
+				for i, row := range rows {
+					cursor := "  "
+					if m.safelistCursor == i {
+						cursor = "> "
+					}
+					label := fmt.Sprintf("%s%s", cursor, row.name)
+					if row.locked {
+						label += glyphs(" 🔒")
+					}
+					if m.safelistCursor == i {
+						s += selected.Render(label) + "\n"
+					} else {
+						s += unselected.Render(label) + "\n"
+					}
+				}
+			}
+			s += "\n" + m.safelistInput.View() + "\n"
+			if m.config.Policy.IsActive() {
+				s += lipgloss.NewStyle().Faint(true).Render(glyphs("\n🔒 = locked by ") + m.config.Policy.Path)
+			}
+			s += lipgloss.NewStyle().Faint(true).Render("\n(Enter to Add, " + hint(m.keys.DeleteItem) + ": delete, tab: switch list, esc: back)")
+
+		case 1:
+			s += warnStyle.Render(glyphs("✓ Processes considered safe to terminate, and where each entry came from:")) + "\n\n"
+
+			entries := m.config.safeToKillEntries()
+			if len(entries) == 0 {
+				s += warnStyle.Render("No safe-to-kill entries.") + "\n"
+			}
+			for i, e := range entries {
 				cursor := "  "
 				if m.safelistCursor == i {
 					cursor = "> "
 				}
-				label := fmt.Sprintf("%s%s", cursor, proc)
+				label := fmt.Sprintf(glyphs("%s%-32s %-16s ← %s"), cursor, e.Process, e.Category, e.Source)
 				if m.safelistCursor == i {
 					s += selected.Render(label) + "\n"
+					if e.Notes != "" {
+						s += unselected.Render("    "+e.Notes) + "\n"
+					}
 				} else {
 					s += unselected.Render(label) + "\n"
 				}
 			}
+			s += lipgloss.NewStyle().Faint(true).Render("\n(tab: switch list, esc: back)")
+
+		case 2:
+			s += warnStyle.Render(glyphs("📦 Community list packs merged into the safe-to-kill lists:")) + "\n\n"
+
+			if len(m.config.Packs) == 0 {
+				s += warnStyle.Render("No packs imported.") + "\n"
+			}
+			for i, p := range m.config.Packs {
+				cursor := "  "
+				if m.safelistCursor == i {
+					cursor = "> "
+				}
+				check := "[ ]"
+				if p.Enabled {
+					check = "[x]"
+				}
+				status := glyphs("✓ verified")
+				switch p.Status {
+				case "missing":
+					status = glyphs("⚠ file missing")
+				case "tampered":
+					status = glyphs("⚠ checksum mismatch")
+				case "invalid":
+					status = glyphs("⚠ invalid pack")
+				}
+				label := fmt.Sprintf("%s%s %s v%s (%d entries) %s", cursor, check, p.Name, p.Version, p.Count, status)
+				if m.safelistCursor == i {
+					s += selected.Render(label) + "\n"
+				} else {
+					s += unselected.Render(label) + "\n"
+				}
+			}
+			s += "\n" + m.safelistInput.View() + "\n"
+			if m.safelistInput.Focused() {
+				s += lipgloss.NewStyle().Faint(true).Render("\n(Enter to Import, empty Enter: back to list, esc: back)")
+			} else {
+				s += lipgloss.NewStyle().Faint(true).Render("\n(" + hint(m.keys.NewItem) + "/Enter: import, " + hint(m.keys.Toggle) + ": enable/disable, " + hint(m.keys.DeleteItem) + ": remove, tab: switch list, esc: back)")
+			}
+		}
+
+		if m.safelistMessage != "" {
+			s += "\n\n" + lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Highlight)).Render(m.safelistMessage)
 		}
-		s += "\n" + m.safelistInput.View() + "\n"
-		s += lipgloss.NewStyle().Faint(true).Render("\n(Enter to Add, d: delete, esc: back)")
 
 	case stateAppEdit:
 		title := "EDIT APP"
@@ -2701,10 +3334,15 @@ func (m model) View() string {
 			title = "NEW APP"
 		}
 		s += titleStyle.Render(title) + "\n\n"
-		s += lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Warn)).Render("Press Ctrl+F to search running processes!") + "\n\n"
+		s += lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Warn)).Render("Press "+hint(m.keys.SearchProc)+" to search running processes, "+hint(m.keys.FindExec)+" to find where it's installed!") + "\n\n"
 		for i := range m.inputs {
 			s += inputStyle.Render(m.inputs[i].View()) + "\n"
 		}
+		if !m.isNewItem && m.config.Sources != nil {
+			app := m.config.Apps[m.cursor]
+			s += unselected.Render(fmt.Sprintf(glyphs("Saved to: name %s • process %s • path %s"),
+				app.describeSource("name"), app.describeSource("process_name"), app.describeSource("exec_path"))) + "\n"
+		}
 		s += lipgloss.NewStyle().Faint(true).Render("\n(Tab to Move, Enter to Save, Esc to Cancel)")
 
 	case stateThemePicker:
@@ -2735,6 +3373,13 @@ func (m model) View() string {
 		s += lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Highlight)).Render("SEARCH RUNNING APPS") + "\n\n"
 		s += m.searchInput.View() + "\n\n"
 		s += m.procList.View()
+		if m.isNewItem {
+			hint := glyphs("tab: mark for bulk add • enter: pick • esc: back")
+			if n := len(m.pickMarked); n > 0 {
+				hint = fmt.Sprintf(glyphs("%d marked • tab: mark/unmark • enter: review and add • esc: back"), n)
+			}
+			s += "\n" + lipgloss.NewStyle().Faint(true).Render(hint)
+		}
 
 	case stateCountdown:
 		var modeStr string
@@ -2753,7 +3398,8 @@ func (m model) View() string {
 		s += fmt.Sprintf("\n   %s IN...\n\n", modeStr)
 		bigNum := lipgloss.NewStyle().Bold(true).Padding(1, 3).Foreground(lipgloss.Color(m.config.Theme.Warn)).Render(fmt.Sprintf("%d", m.countdown))
 		s += fmt.Sprintf("      %s", bigNum)
-		s += "\n\n   Press q to cancel."
+		s += "\n\n   " + lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Warn)).Bold(true).Render(buttonCancel) +
+			lipgloss.NewStyle().Faint(true).Render(fmt.Sprintf("  or press %s / esc", hint(m.keys.Quit)))
 
 	case stateProcessing, stateDone:
 		var modeStr string
@@ -2766,12 +3412,22 @@ func (m model) View() string {
 			modeStr = suspendStyle.Render("SUSPENDING...")
 		case "resume":
 			modeStr = restoreStyle.Render("RESUMING...")
+		case "undo":
+			modeStr = restoreStyle.Render("UNDOING...")
 		default:
 			modeStr = restoreStyle.Render("LAUNCHING...")
 		}
 		s += modeStr + "\n\n"
-		s += m.progress.View() + "\n\n"
+		if display.screenReader {
+			s += fmt.Sprintf("Progress: %.0f%%", m.progPercent*100) + "\n\n"
+		} else {
+			s += m.progress.View() + "\n\n"
+		}
 
+		if m.currentState == stateDone {
+			s += m.viewDoneLog()
+			break
+		}
 		start := 0
 		if len(m.logs) > 5 {
 			start = len(m.logs) - 5
@@ -2779,16 +3435,13 @@ func (m model) View() string {
 		for _, log := range m.logs[start:] {
 			s += base.Render(log) + "\n"
 		}
-		if m.currentState == stateDone {
-			s += "\n" + lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Highlight)).Render("Done! Press any key to return.")
-		}
 
 	case stateHistory:
 		titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Select)).Bold(true).Underline(true)
 		selected := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Select)).Bold(true)
 		unselected := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Text)).Faint(true)
 
-		s += titleStyle.Render("📜 SESSION HISTORY") + "\n\n"
+		s += titleStyle.Render(glyphs("📜 SESSION HISTORY")) + "\n\n"
 
 		if m.history.IsEmpty() {
 			s += lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Warn)).Render("No operations recorded this session.") + "\n"
@@ -2831,56 +3484,54 @@ func (m model) View() string {
 			}
 		}
 
-		s += "\n" + lipgloss.NewStyle().Faint(true).Render("↑/↓: Navigate • u: Undo last • Esc: Back") + "\n"
+		s += "\n" + lipgloss.NewStyle().Faint(true).Render(hint(m.keys.Up)+"/"+hint(m.keys.Down)+glyphs(": Navigate • ")+hint(m.keys.Undo)+glyphs(": Undo last • Esc: Back")) + "\n"
 
 	case stateUndoConfirm:
 		titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Select)).Bold(true).Underline(true)
 		warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Warn))
 
-		s += titleStyle.Render("⚠️  CONFIRM UNDO") + "\n\n"
+		s += titleStyle.Render(glyphs("⚠️  CONFIRM UNDO")) + "\n\n"
 		s += m.undoMessage + "\n\n"
 		s += warnStyle.Render("This will reverse the operation.") + "\n"
 		s += warnStyle.Render("This action cannot be undone.") + "\n\n"
-		s += lipgloss.NewStyle().Faint(true).Render("Enter: Confirm • Esc: Cancel") + "\n"
+		s += lipgloss.NewStyle().Faint(true).Render(glyphs("Enter: Confirm • Esc: Cancel")) + "\n"
 
 	case stateProfileExport:
-		titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Select)).Bold(true).Underline(true)
-		inputStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Highlight)).MarginBottom(1)
-
-		s += titleStyle.Render("💾 EXPORT PROFILE") + "\n\n"
-		s += lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Text)).Render("Save your configuration to share or backup.") + "\n\n"
-
-		for i := range m.inputs {
-			s += inputStyle.Render(m.inputs[i].View()) + "\n"
-		}
-
-		s += "\n" + lipgloss.NewStyle().Faint(true).Render("Tab: Next field • Enter: Export • Esc: Cancel") + "\n"
+		s += m.viewProfileExport()
 
 	case stateProfileImport:
 		titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Select)).Bold(true).Underline(true)
 		warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Warn))
 
-		s += titleStyle.Render("📥 IMPORT PROFILE") + "\n\n"
-		s += warnStyle.Render("⚠️ This will merge the profile with your current config.") + "\n\n"
+		s += titleStyle.Render(glyphs("📥 IMPORT PROFILE")) + "\n\n"
+		s += warnStyle.Render("Nothing is changed until you review the profile on the next screen.") + "\n\n"
 
 		if m.profileList.Items() == nil || len(m.profileList.Items()) == 0 {
-			s += lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Warn)).Render("No profiles found in current directory.") + "\n\n"
+			s += lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Warn)).Render("No profiles found in "+configDir()) + "\n\n"
 			s += lipgloss.NewStyle().Faint(true).Render("Export a profile first (Ctrl+E) or place profile files here.") + "\n"
 		} else {
 			s += m.profileList.View()
 		}
 
-		s += "\n" + lipgloss.NewStyle().Faint(true).Render("↑/↓: Navigate • Enter: Import • Esc: Cancel") + "\n"
+		s += "\n" + lipgloss.NewStyle().Faint(true).Render(glyphs("↑/↓: Navigate • Enter: Review • Esc: Cancel")) + "\n"
 
 	}
 
-	return lipgloss.NewStyle().Padding(2, 4).Render(s)
+	return display.render(lipgloss.NewStyle().Padding(viewPadTop, viewPadLeft).Render(s))
 }
 
 func main() {
+	// Handle --config before anything touches the config directory
+	args, configFlag, err := extractConfigFlag(os.Args[1:])
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	args, displayFlags = extractDisplayFlags(args)
+
 	// Handle version flag
-	if len(os.Args) > 1 {
-		switch os.Args[1] {
+	if len(args) > 0 {
+		switch args[0] {
 		case "--version", "-v", "version":
 			fmt.Printf("╭─────────────────────────────────╮\n")
 			fmt.Printf("│   SceneShift v%-18s│\n", Version)
@@ -2899,11 +3550,34 @@ func main() {
 		}
 	}
 
-	p := tea.NewProgram(initialModel())
-	if _, err := p.Run(); err != nil {
+	if err := resolveConfigFile(configFlag); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(args) > 0 && args[0] == "config" {
+		os.Exit(runConfigCommand(args[1:]))
+	}
+
+	m := initialModel()
+	var opts []tea.ProgramOption
+	if !m.config.DisableMouse {
+		opts = append(opts, tea.WithMouseCellMotion())
+	}
+	p := tea.NewProgram(m, opts...)
+	final, err := p.Run()
+	if err != nil {
 		fmt.Printf("Error running program: %v", err)
 		os.Exit(1)
 	}
+	fm, _ := final.(model)
+	if fm.auditErr != nil {
+		fmt.Printf("Warning: the audit log could not be written: %v\n", fm.auditErr)
+	}
+	if fm.saveErr != nil {
+		fmt.Printf("Warning: configuration was not saved: %v\n", fm.saveErr)
+		os.Exit(1)
+	}
 }
 
 func printHelp() {
@@ -2911,15 +3585,31 @@ func printHelp() {
 
 USAGE:
     SceneShift.exe [OPTIONS]
+    SceneShift.exe config validate [OPTIONS]
 
 OPTIONS:
     --version, -v       Show version information
     --help, -h          Show this help message
+    --config <file>     Use this config.yaml instead of the default
+    --ascii             Plain ASCII instead of emoji, arrows and box drawing
+    --high-contrast     High Contrast theme, without faint text
+    --screen-reader     One line per app, no charts; implies --ascii
+
+ENVIRONMENT:
+    SCENESHIFT_CONFIG   Path to config.yaml (overridden by --config)
+
+CONFIG LOCATION:
+    Default: %%AppData%%\SceneShift\config.yaml
+    theme.yaml, packs, profiles, saved logs and audit.log are stored next to it.
 
 RUNNING:
     Simply run 'SceneShift.exe' to start the TUI interface
 
-KEYBINDINGS (in TUI):
+COMMANDS:
+    config validate     Check config.yaml and theme.yaml and report each
+                        problem with its line and column. Exits 1 on errors.
+
+KEYBINDINGS (in TUI, defaults; remap under hotkeys: in config.yaml):
     K                   Kill selected processes
     S                   Suspend selected processes
     U                   Resume suspended processes
@@ -2928,6 +3618,11 @@ KEYBINDINGS (in TUI):
     Space               Toggle selection
     a                   Select all
     x                   Deselect all
+    o / O               Sort by next column / reverse
+    /                   Filter apps
+    Enter               App details and history
+    P                   Process explorer
+    Ctrl+P              Command palette
 
     n                   New app entry
     e                   Edit selected app
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// --- Config Schema Migrations ---
+//
+// config.yaml carries a schema_version. Files written before it existed are
+// identified by their shape:
+//
+//	1: v2.0    apps, presets and hotkeys only
+//	2: v2.1.0  top-level safelist, suspend/resume hotkeys
+//	3: v2.1.1  protection.exclusion_list and safe_to_kill lists
+//	4: v2.3    schema_version field
+//
+// Each step upgrades the raw YAML document by exactly one version, so a
+// config is walked through every intermediate format in order.
+
+const currentSchemaVersion = 4
+
+type configMigration struct {
+	from  int
+	name  string
+	apply func(raw map[string]interface{}) error
+}
+
+var configMigrations = []configMigration{
+	{from: 1, name: "v2.0 → v2.1.0", apply: migrateV20ToV210},
+	{from: 2, name: "v2.1.0 → v2.1.1", apply: migrateV210ToV211},
+	{from: 3, name: "v2.1.1 → schema 4", apply: func(raw map[string]interface{}) error { return nil }},
+}
+
+// detectSchemaVersion returns the schema_version of a raw config, inferring
+// it from the document's shape for files that predate the field
+func detectSchemaVersion(raw map[string]interface{}) (int, error) {
+	if v, ok := raw["schema_version"]; ok {
+		n, ok := v.(int)
+		if !ok || n < 1 {
+			return 0, fmt.Errorf("invalid schema_version %v", v)
+		}
+		return n, nil
+	}
+	if _, ok := raw["protection"]; ok {
+		return 3, nil
+	}
+	if _, ok := raw["safe_to_kill"]; ok {
+		return 3, nil
+	}
+	if _, ok := raw["safelist"]; ok {
+		return 2, nil
+	}
+	if hotkeys, ok := raw["hotkeys"].(map[string]interface{}); ok {
+		if _, ok := hotkeys["suspend_mode"]; ok {
+			return 2, nil
+		}
+	}
+	return 1, nil
+}
+
+// migrateConfig decodes config.yaml, upgrading it to currentSchemaVersion.
+// It returns the version the file was written with.
+func migrateConfig(data []byte) (Config, int, error) {
+	raw := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return Config{}, 0, err
+	}
+	if raw == nil {
+		raw = map[string]interface{}{}
+	}
+
+	from, err := detectSchemaVersion(raw)
+	if err != nil {
+		return Config{}, 0, err
+	}
+	if from > currentSchemaVersion {
+		return Config{}, from, fmt.Errorf("written by a newer SceneShift (schema %d, this version supports %d); please upgrade", from, currentSchemaVersion)
+	}
+
+	for _, step := range configMigrations {
+		if step.from < from {
+			continue
+		}
+		if err := step.apply(raw); err != nil {
+			return Config{}, from, fmt.Errorf("migration %s failed: %w", glyphs(step.name), err)
+		}
+		raw["schema_version"] = step.from + 1
+	}
+
+	cfg, err := rawToConfig(raw)
+	if err != nil {
+		return Config{}, from, err
+	}
+	return cfg, from, nil
+}
+
+// rawToConfig converts a raw YAML document into a typed Config
+func rawToConfig(raw map[string]interface{}) (Config, error) {
+	var cfg Config
+	data, err := yaml.Marshal(raw)
+	if err != nil {
+		return cfg, err
+	}
+	err = yaml.Unmarshal(data, &cfg)
+	return cfg, err
+}
+
+// migrateV20ToV210 adds the suspend/resume hotkeys and the safelist that
+// were introduced in v2.1.0
+func migrateV20ToV210(raw map[string]interface{}) error {
+	hotkeys, ok := raw["hotkeys"].(map[string]interface{})
+	if !ok {
+		hotkeys = map[string]interface{}{}
+		raw["hotkeys"] = hotkeys
+	}
+	if _, ok := hotkeys["suspend_mode"]; !ok {
+		hotkeys["suspend_mode"] = []interface{}{"S"}
+	}
+	if _, ok := hotkeys["resume_mode"]; !ok {
+		hotkeys["resume_mode"] = []interface{}{"U"}
+	}
+
+	if _, ok := raw["safelist"]; !ok {
+		safelist := make([]interface{}, len(defaultSafelist))
+		for i, name := range defaultSafelist {
+			safelist[i] = name
+		}
+		raw["safelist"] = safelist
+	}
+	return nil
+}
+
+// migrateV210ToV211 moves the v2.1.0 safelist to protection.exclusion_list,
+// adds the curated safe-to-kill lists and assigns safety levels to apps. A
+// safelist the user emptied stays empty; only a missing one gets defaults.
+func migrateV210ToV211(raw map[string]interface{}) error {
+	safelist, ok := raw["safelist"]
+	exclusions, _ := safelist.([]interface{})
+	delete(raw, "safelist")
+	if !ok {
+		for _, name := range getDefaultProtectionList() {
+			exclusions = append(exclusions, name)
+		}
+	}
+	raw["protection"] = map[string]interface{}{"exclusion_list": exclusions}
+
+	if _, ok := raw["safe_to_kill"]; !ok {
+		defaults := getDefaultSafeToKill()
+		raw["safe_to_kill"] = map[string]interface{}{
+			"bloatware":      defaults.Bloatware,
+			"chat_apps":      defaults.ChatApps,
+			"game_launchers": defaults.GameLaunchers,
+			"utilities":      defaults.Utilities,
+		}
+	}
+
+	cfg, err := rawToConfig(raw)
+	if err != nil {
+		return err
+	}
+	apps, _ := raw["apps"].([]interface{})
+	for _, a := range apps {
+		app, ok := a.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if level, _ := app["safety_level"].(string); level == "" {
+			name, _ := app["process_name"].(string)
+			app["safety_level"] = detectSafetyLevel(name, &cfg)
+		}
+	}
+	return nil
+}
+
+// backupBeforeMigration keeps the original file of a migrated config. These
+// backups are never pruned.
+func backupBeforeMigration(data []byte, from int) (string, error) {
+	if err := os.MkdirAll(backupsPath(), 0755); err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("pre-migration-schema%d-%s.yaml", from, time.Now().Format("20060102-150405"))
+	path := filepath.Join(backupsPath(), name)
+	return path, writeFileAtomic(path, data)
+}
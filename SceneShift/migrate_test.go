@@ -0,0 +1,232 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestMigrateConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		version    int
+		exclusions []string
+		safety     string // Of the Discord app
+	}{
+		{
+			name: "v2.0",
+			data: `hotkeys:
+  up: [up, k]
+  kill_mode: [K]
+presets:
+  - name: Gaming
+    key: "1"
+    apps: [Discord]
+apps:
+  - name: Discord
+    process_name: Discord.exe
+    exec_path: C:\Discord\Discord.exe
+    selected: true
+`,
+			version:    1,
+			exclusions: defaultSafelist,
+			safety:     "safe",
+		},
+		{
+			name: "v2.1.0",
+			data: `hotkeys:
+  kill_mode: [K]
+  suspend_mode: [S]
+  resume_mode: [U]
+presets:
+  - name: Gaming
+    key: "1"
+    apps: [Discord]
+apps:
+  - name: Discord
+    process_name: Discord.exe
+    exec_path: C:\Discord\Discord.exe
+    selected: true
+safelist:
+  - explorer.exe
+  - MyAntivirus.exe
+`,
+			version:    2,
+			exclusions: []string{"explorer.exe", "MyAntivirus.exe"},
+			safety:     "safe",
+		},
+		{
+			name: "v2.1.0 emptied safelist",
+			data: `hotkeys:
+  kill_mode: [K]
+  suspend_mode: [S]
+  resume_mode: [U]
+presets:
+  - name: Gaming
+    key: "1"
+    apps: [Discord]
+apps:
+  - name: Discord
+    process_name: Discord.exe
+    exec_path: C:\Discord\Discord.exe
+    selected: true
+safelist: []
+`,
+			version:    2,
+			exclusions: []string{},
+			safety:     "safe",
+		},
+		{
+			name: "v2.1.1",
+			data: `hotkeys:
+  kill_mode: [K]
+  suspend_mode: [S]
+  resume_mode: [U]
+presets:
+  - name: Gaming
+    key: "1"
+    apps: [Discord]
+apps:
+  - name: Discord
+    process_name: Discord.exe
+    exec_path: C:\Discord\Discord.exe
+    selected: true
+    safety_level: caution
+protection:
+  exclusion_list: [explorer.exe]
+safe_to_kill:
+  chat_apps: [Discord.exe]
+`,
+			version:    3,
+			exclusions: []string{"explorer.exe"},
+			safety:     "caution",
+		},
+		{
+			name: "schema_version",
+			data: `schema_version: 4
+hotkeys:
+  kill_mode: [K]
+  suspend_mode: [S]
+  resume_mode: [U]
+presets:
+  - name: Gaming
+    key: "1"
+    apps: [Discord]
+apps:
+  - name: Discord
+    process_name: Discord.exe
+    exec_path: C:\Discord\Discord.exe
+    selected: true
+    safety_level: safe
+protection:
+  exclusion_list: [explorer.exe]
+safe_to_kill:
+  chat_apps: [Discord.exe]
+`,
+			version:    4,
+			exclusions: []string{"explorer.exe"},
+			safety:     "safe",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, from, err := migrateConfig([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("migrateConfig: %v", err)
+			}
+			if from != tt.version {
+				t.Errorf("detected version %d, want %d", from, tt.version)
+			}
+
+			if cfg.SchemaVersion != currentSchemaVersion {
+				t.Errorf("schema_version %d, want %d", cfg.SchemaVersion, currentSchemaVersion)
+			}
+			if !reflect.DeepEqual(cfg.Hotkeys.SuspendMode, []string{"S"}) || !reflect.DeepEqual(cfg.Hotkeys.ResumeMode, []string{"U"}) {
+				t.Errorf("suspend/resume hotkeys %v/%v, want [S]/[U]", cfg.Hotkeys.SuspendMode, cfg.Hotkeys.ResumeMode)
+			}
+			if !reflect.DeepEqual(cfg.Hotkeys.KillMode, []string{"K"}) {
+				t.Errorf("kill hotkey %v, want [K]", cfg.Hotkeys.KillMode)
+			}
+			if !reflect.DeepEqual(cfg.Protection.ExclusionList, tt.exclusions) {
+				t.Errorf("exclusion list %v, want %v", cfg.Protection.ExclusionList, tt.exclusions)
+			}
+			if len(cfg.SafeToKill.ChatApps) == 0 {
+				t.Error("safe-to-kill lists are empty")
+			}
+			wantApps := []AppEntry{{
+				Name:        "Discord",
+				ProcessName: "Discord.exe",
+				ExecPath:    `C:\Discord\Discord.exe`,
+				Selected:    true,
+				SafetyLevel: tt.safety,
+			}}
+			if !reflect.DeepEqual(cfg.Apps, wantApps) {
+				t.Errorf("apps %+v, want %+v", cfg.Apps, wantApps)
+			}
+			wantPresets := []PresetConfig{{Name: "Gaming", Key: "1", Apps: []string{"Discord"}}}
+			if !reflect.DeepEqual(cfg.Presets, wantPresets) {
+				t.Errorf("presets %+v, want %+v", cfg.Presets, wantPresets)
+			}
+
+			// Migrating the result again changes nothing
+			data, err := yaml.Marshal(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			again, from, err := migrateConfig(data)
+			if err != nil {
+				t.Fatalf("second migrateConfig: %v", err)
+			}
+			if from != currentSchemaVersion {
+				t.Errorf("migrated config detected as version %d", from)
+			}
+			if out, _ := yaml.Marshal(again); string(out) != string(data) {
+				t.Errorf("second migration changed the config:\n%s\nwant:\n%s", out, data)
+			}
+		})
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" backup", func(t *testing.T) {
+			defer func(saved string) { configFile = saved }(configFile)
+			configFile = filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configFile, []byte(tt.data), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, _, _, err := loadConfig(); err != nil {
+				t.Fatalf("loadConfig: %v", err)
+			}
+
+			backups, _ := filepath.Glob(filepath.Join(backupsPath(), "pre-migration-*.yaml"))
+			if tt.version == currentSchemaVersion {
+				if len(backups) != 0 {
+					t.Errorf("current config was backed up: %v", backups)
+				}
+				return
+			}
+			if len(backups) != 1 {
+				t.Fatalf("got backups %v, want one", backups)
+			}
+			if want := fmt.Sprintf("pre-migration-schema%d-", tt.version); !strings.HasPrefix(filepath.Base(backups[0]), want) {
+				t.Errorf("backup %s, want %s*", filepath.Base(backups[0]), want)
+			}
+			original, _ := os.ReadFile(backups[0])
+			if string(original) != tt.data {
+				t.Errorf("backup holds %q, want the original file", original)
+			}
+
+			// The migrated file is written back, so the next load does not migrate
+			data, _ := os.ReadFile(configFile)
+			if _, from, err := migrateConfig(data); err != nil || from != currentSchemaVersion {
+				t.Errorf("rewritten config detected as version %d (%v)", from, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// --- Mouse ---
+//
+// With mouse reporting on (the default, see Config.DisableMouse) a click on
+// a table row moves the cursor there and a click on its checkbox toggles it.
+// The wheel scrolls whichever list is on screen. Preset hints under the
+// table and the buttons on the countdown and done screens can be clicked.
+//
+// Row positions are worked out from the same layout helpers the views use.
+// Hints and buttons are found by their text in the rendered screen, so they
+// stay clickable wherever the layout puts them.
+
+// View padding, which offsets every mouse position
+const (
+	viewPadTop  = 2
+	viewPadLeft = 4
+)
+
+// Button labels on the countdown and done screens
+const (
+	buttonCancel = "[ Cancel ]"
+	buttonMenu   = "[ Back to menu ]"
+	buttonQuit   = "[ Quit ]"
+)
+
+func (m model) updateMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.reloadConflict {
+		return m, nil
+	}
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		return m.scrollMouse(-1)
+	case tea.MouseButtonWheelDown:
+		return m.scrollMouse(1)
+	case tea.MouseButtonLeft:
+		if msg.Action != tea.MouseActionPress {
+			return m, nil
+		}
+		return m.clickMouse(msg)
+	}
+	return m, nil
+}
+
+// scrollMouse moves the cursor of the list on screen by one row
+func (m model) scrollMouse(delta int) (tea.Model, tea.Cmd) {
+	up, down := tea.KeyMsg{Type: tea.KeyUp}, tea.KeyMsg{Type: tea.KeyDown}
+	switch m.currentState {
+	case stateMenu:
+		m.moveCursor(delta)
+		m.followCursor()
+		return m, nil
+	case stateProcessExplorer, stateAppDetail, stateHistory, statePresetList,
+		stateExecPicker, stateRepairPaths, stateImportReview, stateDone:
+		// Screens driven by the key map follow its bindings
+		up, down = keyMsgFor(m.keys.Up.Keys()[0]), keyMsgFor(m.keys.Down.Keys()[0])
+	case statePalette, stateThemePicker, stateProcessPicker, stateProfileImport,
+		statePresetAppPicker, stateConfigRecovery, stateSafelistManager:
+		// Arrow keys, which never type into a search or input box
+	default:
+		// Not a list
+		return m, nil
+	}
+	if delta < 0 {
+		return m.Update(up)
+	}
+	return m.Update(down)
+}
+
+func (m model) clickMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	line, col := msg.Y-viewPadTop, msg.X-viewPadLeft
+
+	switch m.currentState {
+	case stateMenu:
+		if label := m.clickedLabel(msg, m.presetHints()...); label != "" {
+			for _, p := range m.config.Presets {
+				if presetHint(p) == label {
+					m.applyPreset(p)
+				}
+			}
+			return m, nil
+		}
+		logo, height := m.menuLayout()
+		// Rows start below the system header and the column titles
+		row := line - strings.Count(m.menuHeader(logo), "\n") - 1
+		rows := m.appRows()
+		if len(m.config.Apps) == 0 || row < 0 || (height > 0 && row >= height) {
+			return m, nil
+		}
+		if row += m.tableOffset; row >= len(rows) {
+			return m, nil
+		}
+		index := rows[row].index
+		// A click on the checkbox, or on the cursor row again, toggles it
+		if (col >= 2 && col < 5) || index == m.cursor {
+			m.config.Apps[index].Selected = !m.config.Apps[index].Selected
+			m.trigger = "manual"
+		}
+		m.cursor = index
+		m.followCursor()
+
+	case stateProcessExplorer:
+		// Rows start below the title and column titles
+		rows := m.explorerRows()
+		row := line - 3
+		height := m.explorerHeight()
+		if m.explorer.procs == nil || row < 0 || (height > 0 && row >= height) {
+			return m, nil
+		}
+		if row += m.explorer.offset; row < len(rows) {
+			m.explorer.pid = rows[row].info.PID
+		}
+
+	case statePalette:
+		// Results start below the title and search box; a click runs one
+		results, _ := m.paletteResults()
+		row := line - 4
+		if row < 0 || row >= m.paletteHeight() {
+			return m, nil
+		}
+		if row += m.palette.offset; row < len(results) {
+			return results[row].run(m)
+		}
+
+	case stateCountdown:
+		if m.clickedLabel(msg, buttonCancel) != "" {
+			return m.cancelCountdown()
+		}
+
+	case stateDone:
+		switch m.clickedLabel(msg, buttonMenu, buttonQuit) {
+		case buttonMenu:
+			return m.leaveDone()
+		case buttonQuit:
+			return m.saveAndQuit()
+		}
+	}
+	return m, nil
+}
+
+// clickedLabel returns which of labels is rendered under the mouse, if any
+func (m model) clickedLabel(msg tea.MouseMsg, labels ...string) string {
+	lines := strings.Split(m.View(), "\n")
+	if msg.Y < 0 || msg.Y >= len(lines) {
+		return ""
+	}
+	line := ansi.Strip(lines[msg.Y])
+	for _, label := range labels {
+		for from := 0; ; {
+			i := strings.Index(line[from:], label)
+			if i < 0 {
+				break
+			}
+			start := ansi.StringWidth(line[:from+i])
+			if msg.X >= start && msg.X < start+ansi.StringWidth(label) {
+				return label
+			}
+			from += i + len(label)
+		}
+	}
+	return ""
+}
+
+// presetHint is how a preset is listed under the main menu table
+func presetHint(p PresetConfig) string {
+	return "[" + p.Key + "] " + p.Name
+}
+
+func (m model) presetHints() []string {
+	var hints []string
+	for _, p := range m.config.Presets {
+		hints = append(hints, presetHint(p))
+	}
+	return hints
+}
+
+// cancelCountdown abandons a pending action before it starts
+func (m model) cancelCountdown() (tea.Model, tea.Cmd) {
+	m.currentState = stateMenu
+	m.countdownID++ // Its pending tick is ignored
+	m.countdown = 5
+	m.statusMessage = "Cancelled " + m.mode
+	return m, nil
+}
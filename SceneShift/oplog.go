@@ -0,0 +1,309 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Operation Log ---
+//
+// When an action or undo finishes, its log is shown in a scrolling pane
+// rather than printed in full, so a preset touching many apps still fits.
+// Each line can be expanded to show the processes it matched and, for
+// failures, the chain of errors behind the message. The pane can be
+// narrowed to problems only, and the whole log saved to a file under the
+// config directory for sharing or later reference.
+
+// logDetail is what is known about one line of the operation log beyond
+// its text
+type logDetail struct {
+	targets []AuditTarget
+	err     error
+}
+
+// openLog resets the log pane for a freshly finished operation
+func (m *model) openLog() {
+	m.logCursor = 0
+	m.logErrors = false
+	m.logExpanded = map[int]bool{}
+	m.logMessage = ""
+	m.refreshLogPane()
+}
+
+// leaveDone returns from the done screen to the main menu
+func (m model) leaveDone() (tea.Model, tea.Cmd) {
+	m.currentState = stateMenu
+	m.logs = []string{}
+	m.logDetails = nil
+	m.progPercent = 0
+	// Show the result of the action without waiting for the next refresh
+	return m, m.restartStats()
+}
+
+// logProblem reports whether a log line is a failure or a blocked action
+func (m model) logProblem(i int) bool {
+	if m.logDetails[i].err != nil {
+		return true
+	}
+	line := m.logs[i]
+	return strings.HasPrefix(line, "[ERR]") || strings.Contains(line, "POLICY]") ||
+		strings.Contains(line, "PROTECTED]")
+}
+
+// visibleLogs returns the indexes of the log lines shown in the pane
+func (m model) visibleLogs() []int {
+	var lines []int
+	for i := range m.logs {
+		if !m.logErrors || m.logProblem(i) {
+			lines = append(lines, i)
+		}
+	}
+	return lines
+}
+
+// logPaneHeight returns how many lines the pane shows
+func (m model) logPaneHeight() int {
+	if m.height == 0 {
+		return 15
+	}
+	// Padding, title, progress bar, then summary, buttons and hints
+	rows := m.height - 4 - 2 - 2 - 7
+	if rows < 5 {
+		rows = 5
+	}
+	return rows
+}
+
+// refreshLogPane re-renders the log into the pane and scrolls it so the
+// entry under the cursor, with its detail, is in view
+func (m *model) refreshLogPane() {
+	width := 100
+	if m.width > 0 {
+		width = m.width - 2*viewPadLeft
+	}
+	m.logPane.Width = width
+	m.logPane.Height = m.logPaneHeight()
+
+	content, top, bottom := m.renderLog(width)
+	m.logPane.SetContent(content)
+	if bottom-top >= m.logPane.Height {
+		bottom = top + m.logPane.Height - 1
+	}
+	if top < m.logPane.YOffset {
+		m.logPane.SetYOffset(top)
+	} else if bottom >= m.logPane.YOffset+m.logPane.Height {
+		m.logPane.SetYOffset(bottom - m.logPane.Height + 1)
+	}
+}
+
+// renderLog renders the visible log lines, returning the first and last
+// rendered line of the entry under the cursor
+func (m model) renderLog(width int) (content string, top, bottom int) {
+	base := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Text))
+	problem := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Warn))
+	selected := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Select)).Bold(true)
+	faint := lipgloss.NewStyle().Faint(true)
+
+	var lines []string
+	visible := m.visibleLogs()
+	if len(visible) == 0 {
+		lines = append(lines, faint.Render("  No problems to show"))
+	}
+	for _, i := range visible {
+		_, hasDetail := m.logDetails[i]
+		cursor, marker := "  ", "  "
+		if i == m.logCursor {
+			cursor = "> "
+			top = len(lines)
+		}
+		if hasDetail {
+			marker = glyphs("▸ ")
+			if m.logExpanded[i] {
+				marker = glyphs("▾ ")
+			}
+		}
+		style := base
+		switch {
+		case i == m.logCursor:
+			style = selected
+		case m.logProblem(i):
+			style = problem
+		}
+		lines = append(lines, style.Render(truncate(cursor+marker+m.logs[i], width)))
+
+		if hasDetail && m.logExpanded[i] {
+			for _, d := range m.logDetailLines(i) {
+				lines = append(lines, faint.Render(truncate("      "+d, width)))
+			}
+		}
+		if i == m.logCursor {
+			bottom = len(lines) - 1
+		}
+	}
+	return strings.Join(lines, "\n"), top, bottom
+}
+
+// logDetailLines describes the processes and errors behind a log line
+func (m model) logDetailLines(i int) []string {
+	detail := m.logDetails[i]
+	var lines []string
+	if len(detail.targets) == 0 {
+		lines = append(lines, "No processes matched")
+	}
+	for _, t := range detail.targets {
+		line := fmt.Sprintf("PID %-8d %s", t.PID, orDash(t.Exe))
+		if t.Cmdline != "" && t.Cmdline != t.Exe {
+			line += "  " + t.Cmdline
+		}
+		lines = append(lines, line)
+	}
+	for j, e := range errorChain(detail.err) {
+		if j == 0 {
+			lines = append(lines, "Error: "+e)
+		} else {
+			lines = append(lines, "  caused by: "+e)
+		}
+	}
+	return lines
+}
+
+// errorChain lists an error and each error it wraps, with the wrapped text
+// trimmed from the wrapping message
+func errorChain(err error) []string {
+	var msgs []string
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		msgs = append(msgs, e.Error())
+	}
+	for i := 0; i < len(msgs)-1; i++ {
+		if trimmed := strings.TrimSuffix(msgs[i], ": "+msgs[i+1]); trimmed != "" {
+			msgs[i] = trimmed
+		}
+	}
+	return msgs
+}
+
+// moveLogCursor moves the cursor by delta visible entries
+func (m *model) moveLogCursor(delta int) {
+	visible := m.visibleLogs()
+	if len(visible) == 0 {
+		return
+	}
+	pos := 0
+	for j, i := range visible {
+		if i == m.logCursor {
+			pos = j
+		}
+	}
+	pos += delta
+	if pos < 0 {
+		pos = 0
+	}
+	if pos >= len(visible) {
+		pos = len(visible) - 1
+	}
+	m.logCursor = visible[pos]
+}
+
+// saveLog writes the whole log, with detail, to a file under the config
+// directory and returns its path
+func (m model) saveLog() (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "SceneShift %s log, %s\n\n", m.mode, time.Now().Format("2006-01-02 15:04:05"))
+	for i, line := range m.logs {
+		b.WriteString(line + "\n")
+		if _, ok := m.logDetails[i]; ok {
+			for _, d := range m.logDetailLines(i) {
+				b.WriteString("    " + d + "\n")
+			}
+		}
+	}
+
+	path := filepath.Join(logsPath(), fmt.Sprintf("sceneshift-%s-%s.log", m.mode, time.Now().Format("2006-01-02-150405")))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return "", fmt.Errorf("failed to write log: %v", err)
+	}
+	return path, nil
+}
+
+func (m model) updateDone(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m.saveAndQuit()
+	case msg.String() == "esc":
+		return m.leaveDone()
+	case key.Matches(msg, m.keys.Up):
+		m.moveLogCursor(-1)
+	case key.Matches(msg, m.keys.Down):
+		m.moveLogCursor(1)
+	case msg.String() == "pgup":
+		m.moveLogCursor(-m.logPaneHeight())
+	case msg.String() == "pgdown":
+		m.moveLogCursor(m.logPaneHeight())
+	case msg.String() == "home":
+		m.moveLogCursor(-len(m.logs))
+	case msg.String() == "end":
+		m.moveLogCursor(len(m.logs))
+	case msg.String() == "enter", key.Matches(msg, m.keys.Toggle):
+		if _, ok := m.logDetails[m.logCursor]; ok {
+			m.logExpanded[m.logCursor] = !m.logExpanded[m.logCursor]
+		}
+	case key.Matches(msg, m.keys.LogProblems):
+		m.logErrors = !m.logErrors
+		if m.logErrors && (m.logCursor >= len(m.logs) || !m.logProblem(m.logCursor)) {
+			m.moveLogCursor(0)
+		}
+	case key.Matches(msg, m.keys.SaveLog):
+		if path, err := m.saveLog(); err != nil {
+			m.logMessage = glyphs("❌ ") + err.Error()
+		} else {
+			m.logMessage = glyphs("💾 Log saved to ") + path
+		}
+	}
+	m.refreshLogPane()
+	return m, nil
+}
+
+func (m model) viewDoneLog() string {
+	highlight := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Highlight))
+	buttonStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Select)).Bold(true)
+	faint := lipgloss.NewStyle().Faint(true)
+
+	var s string
+	s += m.logPane.View() + "\n"
+
+	problems := 0
+	for i := range m.logs {
+		if m.logProblem(i) {
+			problems++
+		}
+	}
+	info := fmt.Sprintf("%d lines, %d problems", len(m.logs), problems)
+	if m.logErrors {
+		info += " (showing problems only)"
+	}
+	if !m.logPane.AtTop() || !m.logPane.AtBottom() {
+		info += fmt.Sprintf(glyphs(" • %d%%"), int(m.logPane.ScrollPercent()*100))
+	}
+	s += faint.Render(info) + "\n\n"
+
+	if m.logMessage != "" {
+		s += highlight.Render(m.logMessage) + "\n"
+	} else {
+		s += highlight.Render("Done!") + "\n"
+	}
+	s += buttonStyle.Render(buttonMenu) + "  " + buttonStyle.Render(buttonQuit) + "\n"
+	s += faint.Render(fmt.Sprintf(glyphs("%s/%s: select • enter: details • %s: problems only • %s: save log • esc: back to menu • %s: quit"),
+		hint(m.keys.Up), hint(m.keys.Down), hint(m.keys.LogProblems), hint(m.keys.SaveLog), hint(m.keys.Quit)))
+	return s
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// --- Safe-to-Kill List Packs ---
+//
+// A pack is a versioned YAML or JSON file of safe-to-kill entries shipped
+// alongside a "<file>.sha256" manifest in sha256sum format. Imported packs
+// are copied into the packs directory next to config.yaml and referenced
+// from config.yaml, so they can be enabled or disabled without touching the
+// user's own safe_to_kill lists.
+
+// PackRef records an imported pack in config.yaml
+type PackRef struct {
+	Name    string `yaml:"name"`
+	Version string `yaml:"version"`
+	File    string `yaml:"file"`
+	SHA256  string `yaml:"sha256"`
+	Enabled bool   `yaml:"enabled"`
+	Status  string `yaml:"-"` // "ok", "missing", "tampered", "invalid"
+	Count   int    `yaml:"-"` // Entries loaded for this platform
+
+	Source configLayer `yaml:"-"` // Layer the pack was listed in
+}
+
+// SafelistPack is the on-disk format of a list pack
+type SafelistPack struct {
+	Name        string      `yaml:"name" json:"name"`
+	Version     string      `yaml:"version" json:"version"`
+	Description string      `yaml:"description,omitempty" json:"description,omitempty"`
+	Author      string      `yaml:"author,omitempty" json:"author,omitempty"`
+	Entries     []PackEntry `yaml:"entries" json:"entries"`
+}
+
+// PackEntry is a single process listed by a pack
+type PackEntry struct {
+	Process  string `yaml:"process" json:"process"`
+	Category string `yaml:"category" json:"category"`
+	Platform string `yaml:"platform,omitempty" json:"platform,omitempty"`
+	Notes    string `yaml:"notes,omitempty" json:"notes,omitempty"`
+}
+
+// SafeToKillEntry is a resolved safe-to-kill entry along with where it came from
+type SafeToKillEntry struct {
+	Process  string
+	Category string
+	Notes    string
+	Source   string
+}
+
+// safeToKillEntries merges the config lists with all enabled packs
+func (cfg *Config) safeToKillEntries() []SafeToKillEntry {
+	var entries []SafeToKillEntry
+	add := func(category string, procs []string) {
+		for _, p := range procs {
+			entries = append(entries, SafeToKillEntry{Process: p, Category: category, Source: "config"})
+		}
+	}
+	add("bloatware", cfg.SafeToKill.Bloatware)
+	add("chat_apps", cfg.SafeToKill.ChatApps)
+	add("game_launchers", cfg.SafeToKill.GameLaunchers)
+	add("utilities", cfg.SafeToKill.Utilities)
+
+	return append(entries, cfg.PackEntries...)
+}
+
+// packMatchesPlatform reports whether a pack entry applies to this OS
+func packMatchesPlatform(platform string) bool {
+	switch strings.ToLower(strings.TrimSpace(platform)) {
+	case "", "any", "all":
+		return true
+	default:
+		return strings.EqualFold(platform, runtime.GOOS)
+	}
+}
+
+// parsePack decodes a pack as JSON or YAML depending on its extension
+func parsePack(filename string, data []byte) (SafelistPack, error) {
+	var pack SafelistPack
+	var err error
+	if strings.EqualFold(filepath.Ext(filename), ".json") {
+		err = json.Unmarshal(data, &pack)
+	} else {
+		err = yaml.Unmarshal(data, &pack)
+	}
+	if err != nil {
+		return pack, fmt.Errorf("invalid pack format: %v", err)
+	}
+
+	if pack.Name == "" {
+		return pack, fmt.Errorf("pack has no name")
+	}
+	if pack.Version == "" {
+		return pack, fmt.Errorf("pack %s has no version", pack.Name)
+	}
+	if len(pack.Entries) == 0 {
+		return pack, fmt.Errorf("pack %s has no entries", pack.Name)
+	}
+	for i, e := range pack.Entries {
+		if strings.TrimSpace(e.Process) == "" {
+			return pack, fmt.Errorf("pack %s: entry %d has no process name", pack.Name, i+1)
+		}
+	}
+	return pack, nil
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// readPackManifest reads the digest from a sha256sum-style manifest
+func readPackManifest(packPath string) (string, error) {
+	data, err := os.ReadFile(packPath + ".sha256")
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("missing manifest %s.sha256", filepath.Base(packPath))
+	} else if err != nil {
+		return "", fmt.Errorf("could not read manifest: %v", err)
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("manifest %s.sha256 is empty", filepath.Base(packPath))
+	}
+	return strings.ToLower(fields[0]), nil
+}
+
+var packFileSanitizer = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// importPack verifies a pack against its manifest, copies it into packsPath
+// and registers it in the config. Re-importing a pack with the same name
+// replaces the previous version.
+func importPack(cfg *Config, path string) (PackRef, error) {
+	path = strings.Trim(strings.TrimSpace(path), `"`)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return PackRef{}, fmt.Errorf("failed to read pack: %v", err)
+	}
+
+	expected, err := readPackManifest(path)
+	if err != nil {
+		return PackRef{}, err
+	}
+	actual := sha256Hex(data)
+	if expected != actual {
+		return PackRef{}, fmt.Errorf(glyphs("checksum mismatch: manifest %s…, file %s…"), expected[:min(12, len(expected))], actual[:12])
+	}
+
+	pack, err := parsePack(path, data)
+	if err != nil {
+		return PackRef{}, err
+	}
+
+	if err := os.MkdirAll(packsPath(), 0755); err != nil {
+		return PackRef{}, fmt.Errorf("failed to create %s: %v", packsPath(), err)
+	}
+	filename := packFileSanitizer.ReplaceAllString(pack.Name, "_") + strings.ToLower(filepath.Ext(path))
+	if err := os.WriteFile(filepath.Join(packsPath(), filename), data, 0644); err != nil {
+		return PackRef{}, fmt.Errorf("failed to store pack: %v", err)
+	}
+
+	ref := PackRef{
+		Name:    pack.Name,
+		Version: pack.Version,
+		File:    filename,
+		SHA256:  actual,
+		Enabled: true,
+	}
+
+	replaced := false
+	for i := range cfg.Packs {
+		if strings.EqualFold(cfg.Packs[i].Name, pack.Name) {
+			ref.Source = cfg.Packs[i].Source
+			cfg.Packs[i] = ref
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		cfg.Packs = append(cfg.Packs, ref)
+	}
+
+	loadPacks(cfg)
+	for _, loaded := range cfg.Packs {
+		if loaded.Name == ref.Name {
+			return loaded, nil
+		}
+	}
+	return ref, nil
+}
+
+// removePack unregisters a pack and deletes its stored copy
+func removePack(cfg *Config, index int) {
+	if index < 0 || index >= len(cfg.Packs) {
+		return
+	}
+	_ = os.Remove(filepath.Join(packsPath(), cfg.Packs[index].File))
+	cfg.Packs = append(cfg.Packs[:index], cfg.Packs[index+1:]...)
+	loadPacks(cfg)
+}
+
+// loadPacks re-reads every enabled pack, verifying it against the digest
+// recorded at import time, and rebuilds cfg.PackEntries
+func loadPacks(cfg *Config) {
+	cfg.PackEntries = nil
+
+	for i := range cfg.Packs {
+		ref := &cfg.Packs[i]
+		ref.Count = 0
+
+		data, err := os.ReadFile(filepath.Join(packsPath(), ref.File))
+		if err != nil {
+			ref.Status = "missing"
+			continue
+		}
+		if !strings.EqualFold(sha256Hex(data), ref.SHA256) {
+			ref.Status = "tampered"
+			continue
+		}
+		pack, err := parsePack(ref.File, data)
+		if err != nil {
+			ref.Status = "invalid"
+			continue
+		}
+		ref.Status = "ok"
+
+		source := fmt.Sprintf("%s v%s", pack.Name, pack.Version)
+		for _, e := range pack.Entries {
+			if !packMatchesPlatform(e.Platform) {
+				continue
+			}
+			ref.Count++
+			if !ref.Enabled {
+				continue
+			}
+			cfg.PackEntries = append(cfg.PackEntries, SafeToKillEntry{
+				Process:  strings.TrimSpace(e.Process),
+				Category: e.Category,
+				Notes:    e.Notes,
+				Source:   source,
+			})
+		}
+	}
+}
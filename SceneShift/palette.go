@@ -0,0 +1,392 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sahilm/fuzzy"
+)
+
+// --- Command Palette ---
+//
+// Ctrl+P opens a palette that fuzzy-searches configured apps, presets,
+// themes, running processes and menu actions from any browsing screen.
+// Choosing an app jumps to it in the table, a preset is applied, a theme is
+// switched to, a process opens in the explorer and an action runs exactly
+// as its menu key would. Running processes are listed in the background so
+// the palette opens instantly.
+
+// paletteItem is one entry the palette can find
+type paletteItem struct {
+	kind   string // App, Preset, Theme, Process or Action
+	title  string
+	detail string
+	run    func(m model) (tea.Model, tea.Cmd)
+}
+
+// paletteState is the command palette's part of the model
+type paletteState struct {
+	prev    state // Screen to return to on esc
+	items   []paletteItem
+	procs   []paletteItem // Running processes, once listed
+	input   textinput.Model
+	matches []fuzzy.Match // Indexes into all(); nil until something is typed
+	cursor  int
+	offset  int
+}
+
+// paletteProcsMsg delivers the running processes listed for the palette
+type paletteProcsMsg []processItem
+
+// all returns the static entries followed by the running processes
+func (p paletteState) all() []paletteItem {
+	return append(append([]paletteItem(nil), p.items...), p.procs...)
+}
+
+// paletteSource adapts entries for fuzzy matching on their title
+type paletteSource []paletteItem
+
+func (s paletteSource) String(i int) string { return s[i].title }
+func (s paletteSource) Len() int            { return len(s) }
+
+// canOpenPalette reports whether the current screen is browsing rather than
+// typing or confirming, so Ctrl+P can open the palette
+func (m model) canOpenPalette() bool {
+	switch m.currentState {
+	case stateMenu:
+		return !m.filtering
+	case statePresetList, stateThemePicker, stateHistory, stateDiagnostics, stateDone:
+		return true
+	case stateAppDetail:
+		return m.detailConfirm == ""
+	case stateProcessExplorer:
+		return !m.explorer.searching && m.explorer.confirm == ""
+	}
+	return false
+}
+
+// startPalette opens the palette over the current screen
+func (m model) startPalette() (tea.Model, tea.Cmd) {
+	m.palette = paletteState{prev: m.currentState, items: m.paletteItems()}
+	m.palette.input = textinput.New()
+	m.palette.input.Prompt = "> "
+	m.palette.input.Placeholder = "Search apps, presets, themes, processes and actions"
+	m.palette.input.CharLimit = 64
+	m.currentState = statePalette
+	return m, tea.Batch(m.palette.input.Focus(), listPaletteProcs)
+}
+
+// listPaletteProcs lists running processes for the palette
+func listPaletteProcs() tea.Msg {
+	var procs []processItem
+	for _, item := range fetchRunningProcesses() {
+		procs = append(procs, item.(processItem))
+	}
+	return paletteProcsMsg(procs)
+}
+
+// paletteItems builds the entries that come from the config and key map
+func (m model) paletteItems() []paletteItem {
+	var items []paletteItem
+
+	for _, app := range m.config.Apps {
+		name := app.Name
+		items = append(items, paletteItem{kind: "App", title: name, detail: app.ProcessName,
+			run: func(m model) (tea.Model, tea.Cmd) {
+				m.currentState = stateMenu
+				m.filterInput.SetValue("")
+				if i := findApp(m.config.Apps, name); i >= 0 {
+					m.cursor = i
+				}
+				m.followCursor()
+				return m, nil
+			}})
+	}
+
+	for _, p := range m.config.Presets {
+		preset := p
+		detail := strings.Join(preset.Apps, ", ")
+		if preset.Key != "" {
+			detail = fmt.Sprintf("[%s] %s", preset.Key, detail)
+		}
+		items = append(items, paletteItem{kind: "Preset", title: preset.Name, detail: detail,
+			run: func(m model) (tea.Model, tea.Cmd) {
+				m.currentState = stateMenu
+				m.applyPreset(preset)
+				m.statusMessage = fmt.Sprintf("Applied preset %s", preset.Name)
+				return m, nil
+			}})
+	}
+
+	for _, t := range themePresets {
+		theme := t
+		items = append(items, paletteItem{kind: "Theme", title: theme.Name,
+			run: func(m model) (tea.Model, tea.Cmd) {
+				m.currentState = stateMenu
+				m.config.Theme = theme
+				m.saveConfig()
+				return m, nil
+			}})
+	}
+
+	actions := []struct {
+		title   string
+		binding key.Binding
+	}{
+		{"Kill selected apps", m.keys.Kill},
+		{"Suspend selected apps", m.keys.Suspend},
+		{"Resume selected apps", m.keys.Resume},
+		{"Restore selected apps", m.keys.Restore},
+		{"Select all apps", m.keys.SelectAll},
+		{"Deselect all apps", m.keys.DeselectAll},
+		{"New app", m.keys.NewItem},
+		{"Filter apps", m.keys.Filter},
+		{"Sort apps by next column", m.keys.Sort},
+		{"Manage presets", m.keys.PresetMenu},
+		{"Change theme", m.keys.ThemeMenu},
+		{"Manage exclusion list", m.keys.SafelistMenu},
+		{"Session history", m.keys.History},
+		{"Undo last operation", m.keys.Undo},
+		{"Export profile", m.keys.Export},
+		{"Import profile", m.keys.Import},
+		{"Repair missing paths", m.keys.RepairPaths},
+		{"Process explorer", m.keys.Explorer},
+		{"Toggle help", m.keys.Help},
+	}
+	for _, a := range actions {
+		binding := a.binding
+		items = append(items, paletteItem{kind: "Action", title: a.title, detail: binding.Help().Key,
+			run: func(m model) (tea.Model, tea.Cmd) {
+				m.currentState = stateMenu
+				if len(binding.Keys()) == 0 {
+					return m, nil
+				}
+				// Run it exactly as the menu key would
+				return m.Update(keyMsgFor(binding.Keys()[0]))
+			}})
+	}
+	return items
+}
+
+// keyMsgFor builds the key press a bound key name stands for, e.g. "K",
+// "ctrl+e" or "alt+1"
+func keyMsgFor(k string) tea.KeyMsg {
+	msg := tea.KeyMsg{}
+	if rest, ok := strings.CutPrefix(k, "alt+"); ok && rest != "" {
+		msg.Alt = true
+		k = rest
+	}
+	if r := []rune(k); len(r) == 1 && k != " " {
+		msg.Type, msg.Runes = tea.KeyRunes, r
+		return msg
+	}
+	if k == " " {
+		msg.Type = tea.KeySpace
+		return msg
+	}
+	// Named keys: find the key type whose name matches
+	for t := tea.KeyType(-128); t < 128; t++ {
+		if t != tea.KeyRunes && t.String() == k {
+			msg.Type = t
+			return msg
+		}
+	}
+	msg.Type, msg.Runes = tea.KeyRunes, []rune(k)
+	return msg
+}
+
+// paletteResults returns the entries to list, best match first
+func (m model) paletteResults() ([]paletteItem, [][]int) {
+	all := m.palette.all()
+	if strings.TrimSpace(m.palette.input.Value()) == "" {
+		return all, nil
+	}
+	results := make([]paletteItem, len(m.palette.matches))
+	highlights := make([][]int, len(m.palette.matches))
+	for i, match := range m.palette.matches {
+		results[i] = all[match.Index]
+		highlights[i] = match.MatchedIndexes
+	}
+	return results, highlights
+}
+
+// refilterPalette matches the query against every entry
+func (m *model) refilterPalette() {
+	query := strings.TrimSpace(m.palette.input.Value())
+	m.palette.matches = nil
+	if query != "" {
+		// Best first; ties keep the order of all(): apps, presets, themes,
+		// actions, then processes
+		m.palette.matches = fuzzy.FindFrom(query, paletteSource(m.palette.all()))
+	}
+	results, _ := m.paletteResults()
+	if m.palette.cursor >= len(results) {
+		m.palette.cursor = len(results) - 1
+	}
+	if m.palette.cursor < 0 {
+		m.palette.cursor = 0
+	}
+	m.followPalette()
+}
+
+// paletteHeight returns how many entries fit on screen
+func (m model) paletteHeight() int {
+	if m.height == 0 {
+		return 15
+	}
+	// Padding, title, input, count and hints
+	rows := m.height - 4 - 2 - 2 - 2 - 2
+	if rows < 3 {
+		rows = 3
+	}
+	return rows
+}
+
+func (m *model) followPalette() {
+	height := m.paletteHeight()
+	if m.palette.cursor < m.palette.offset {
+		m.palette.offset = m.palette.cursor
+	}
+	if m.palette.cursor >= m.palette.offset+height {
+		m.palette.offset = m.palette.cursor - height + 1
+	}
+}
+
+func (m model) updatePalette(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	results, _ := m.paletteResults()
+	switch {
+	case msg.String() == "esc", key.Matches(msg, m.keys.Palette):
+		m.currentState = m.palette.prev
+		return m, nil
+	case msg.String() == "enter":
+		if len(results) == 0 {
+			return m, nil
+		}
+		return results[m.palette.cursor].run(m)
+	case msg.String() == "up", msg.String() == "ctrl+k":
+		if m.palette.cursor > 0 {
+			m.palette.cursor--
+		}
+		m.followPalette()
+		return m, nil
+	case msg.String() == "down", msg.String() == "ctrl+j":
+		if m.palette.cursor < len(results)-1 {
+			m.palette.cursor++
+		}
+		m.followPalette()
+		return m, nil
+	case msg.String() == "pgup", msg.String() == "pgdown":
+		step := m.paletteHeight()
+		if msg.String() == "pgup" {
+			step = -step
+		}
+		m.palette.cursor += step
+		if m.palette.cursor >= len(results) {
+			m.palette.cursor = len(results) - 1
+		}
+		if m.palette.cursor < 0 {
+			m.palette.cursor = 0
+		}
+		m.followPalette()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	before := m.palette.input.Value()
+	m.palette.input, cmd = m.palette.input.Update(msg)
+	if m.palette.input.Value() != before {
+		m.palette.cursor = 0
+		m.palette.offset = 0
+		m.refilterPalette()
+	}
+	return m, cmd
+}
+
+// updatePaletteProcs adds the listed processes, each opening the explorer
+// searched to it
+func (m model) updatePaletteProcs(msg paletteProcsMsg) (tea.Model, tea.Cmd) {
+	if m.currentState != statePalette {
+		return m, nil
+	}
+	m.palette.procs = nil
+	for _, p := range msg {
+		exe := p.exe
+		m.palette.procs = append(m.palette.procs, paletteItem{kind: "Process", title: exe, detail: p.path,
+			run: func(m model) (tea.Model, tea.Cmd) {
+				next, cmd := m.startProcessExplorer()
+				nm := next.(model)
+				nm.explorer.search = textinput.New()
+				nm.explorer.search.Prompt = "/"
+				nm.explorer.search.SetValue(exe)
+				return nm, cmd
+			}})
+	}
+	m.refilterPalette()
+	return m, nil
+}
+
+func (m model) viewPalette() string {
+	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Select)).Bold(true).Underline(true)
+	kindStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Highlight))
+	selected := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Select)).Bold(true)
+	unselected := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Text))
+	matched := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Select)).Bold(true).Underline(true)
+	faint := lipgloss.NewStyle().Faint(true)
+
+	var s string
+	s += titleStyle.Render("COMMAND PALETTE") + "\n\n"
+	s += m.palette.input.View() + "\n\n"
+
+	results, highlights := m.paletteResults()
+	start := m.palette.offset
+	end := start + m.paletteHeight()
+	if end > len(results) {
+		end = len(results)
+	}
+	if len(results) == 0 {
+		s += faint.Render("  No matches") + "\n"
+	}
+	for i := start; i < end; i++ {
+		item := results[i]
+		style := unselected
+		cursor := "  "
+		if i == m.palette.cursor {
+			style, cursor = selected, "> "
+		}
+
+		// Underline the characters the query matched
+		var hits map[int]bool
+		if highlights != nil {
+			hits = map[int]bool{}
+			for _, h := range highlights[i] {
+				hits[h] = true
+			}
+		}
+		var title strings.Builder
+		for j, r := range item.title {
+			if hits[j] {
+				title.WriteString(matched.Render(string(r)))
+			} else {
+				title.WriteString(style.Render(string(r)))
+			}
+		}
+
+		s += style.Render(cursor) + kindStyle.Render(fmt.Sprintf("%-8s", item.kind)) + title.String()
+		if item.detail != "" {
+			s += faint.Render("  " + truncate(item.detail, 60))
+		}
+		s += "\n"
+	}
+
+	count := fmt.Sprintf("%d results", len(results))
+	if m.palette.procs == nil {
+		count += glyphs(" • listing processes...")
+	}
+	s += "\n" + faint.Render(count) + "\n"
+	s += faint.Render(fmt.Sprintf(glyphs("↑/↓: select • enter: go • esc/%s: close"), hint(m.keys.Palette))) + "\n"
+	return s
+}
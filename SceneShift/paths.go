@@ -0,0 +1,214 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// --- Config Location ---
+//
+// config.yaml is resolved once at startup, in order of precedence:
+//
+//  1. --config <file>
+//  2. SCENESHIFT_CONFIG
+//  3. The per-user config directory (%AppData%\SceneShift on Windows,
+//     $XDG_CONFIG_HOME/sceneshift or ~/.config/sceneshift elsewhere)
+//
+// theme.yaml, imported packs, profiles, saved operation logs and the audit
+// log live next to it.
+
+// configFile is the resolved path of config.yaml
+var configFile = "config.yaml"
+
+// configFileExplicit is set when the path came from the flag or environment
+var configFileExplicit bool
+
+// resolveConfigFile picks the config.yaml location and creates its directory
+func resolveConfigFile(flagPath string) error {
+	path := flagPath
+	if path == "" {
+		path = os.Getenv("SCENESHIFT_CONFIG")
+	}
+	configFileExplicit = path != ""
+
+	if path == "" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return fmt.Errorf("could not determine user config directory: %w", err)
+		}
+		appDir := "sceneshift"
+		if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+			appDir = "SceneShift"
+		}
+		path = filepath.Join(dir, appDir, "config.yaml")
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("invalid config path %s: %w", path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(abs), 0755); err != nil {
+		return fmt.Errorf("could not create config directory: %w", err)
+	}
+	configFile = abs
+	return nil
+}
+
+// configDir returns the directory holding config.yaml
+func configDir() string {
+	return filepath.Dir(configFile)
+}
+
+// packsPath returns the directory imported list packs are stored in
+func packsPath() string {
+	return filepath.Join(configDir(), "packs")
+}
+
+// logsPath returns the directory operation logs are saved to
+func logsPath() string {
+	return filepath.Join(configDir(), "logs")
+}
+
+// themeFile returns the path of theme.yaml
+func themeFile() string {
+	return filepath.Join(configDir(), "theme.yaml")
+}
+
+// configRelPath resolves a path from config.yaml relative to its directory
+func configRelPath(path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(configDir(), path)
+}
+
+// migrateLegacyConfig copies a config that older versions kept next to the
+// executable into the per-user config directory, along with its theme,
+// imported packs and exported profiles. It runs only when the new location
+// has no config yet and no explicit path was given, and leaves the old files
+// in place. config.yaml is copied last, so an interrupted migration is
+// retried on the next launch; files already copied are kept. Returns the
+// directory migrated from, if any.
+func migrateLegacyConfig() (string, error) {
+	if configFileExplicit {
+		return "", nil
+	}
+	if _, err := os.Stat(configFile); !errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return "", nil
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	legacyDir := filepath.Dir(exe)
+	if strings.EqualFold(legacyDir, configDir()) {
+		return "", nil
+	}
+	if _, err := os.Stat(filepath.Join(legacyDir, "config.yaml")); err != nil {
+		return "", nil
+	}
+
+	migrate := func(src, dst string) error {
+		if err := copyFile(src, dst); err != nil && !errors.Is(err, os.ErrExist) {
+			return err
+		}
+		return nil
+	}
+
+	if err := migrate(filepath.Join(legacyDir, "theme.yaml"), themeFile()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("could not migrate theme.yaml: %w", err)
+	}
+
+	// Imported packs are referenced relative to the config directory
+	if entries, err := os.ReadDir(filepath.Join(legacyDir, "packs")); err == nil {
+		if err := os.MkdirAll(packsPath(), 0755); err != nil {
+			return "", fmt.Errorf("could not migrate packs: %w", err)
+		}
+		for _, e := range entries {
+			if e.IsDir() {
+				continue
+			}
+			if err := migrate(filepath.Join(legacyDir, "packs", e.Name()), filepath.Join(packsPath(), e.Name())); err != nil {
+				return "", fmt.Errorf("could not migrate pack %s: %w", e.Name(), err)
+			}
+		}
+	}
+
+	// Profiles are listed from the config directory
+	if entries, err := os.ReadDir(legacyDir); err == nil {
+		for _, e := range entries {
+			if e.IsDir() {
+				continue
+			}
+			src := filepath.Join(legacyDir, e.Name())
+			data, err := os.ReadFile(src)
+			if err != nil || !isProfileFile(e.Name(), data) {
+				continue
+			}
+			if err := migrate(src, filepath.Join(configDir(), e.Name())); err != nil {
+				return "", fmt.Errorf("could not migrate profile %s: %w", e.Name(), err)
+			}
+		}
+	}
+
+	if err := migrate(filepath.Join(legacyDir, "config.yaml"), configFile); err != nil {
+		return "", fmt.Errorf("could not migrate config.yaml: %w", err)
+	}
+	return legacyDir, nil
+}
+
+// copyFile copies src to dst, failing if dst already exists. A partial copy
+// is removed.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return err
+	}
+	return nil
+}
+
+// extractConfigFlag removes --config <file> or --config=<file> from args
+func extractConfigFlag(args []string) ([]string, string, error) {
+	var rest []string
+	path := ""
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--config":
+			if i+1 >= len(args) {
+				return nil, "", fmt.Errorf("%s requires a file path", arg)
+			}
+			path = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--config="):
+			path = strings.TrimPrefix(arg, "--config=")
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return rest, path, nil
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// --- Config Persistence ---
+//
+// config.yaml and theme.yaml are written to a temporary file in the same
+// directory and renamed into place, so a crash or full disk never leaves a
+// truncated file behind. Before config.yaml is replaced, the previous version
+// is copied into backups/ and only the newest backups are kept.
+
+const defaultBackupCount = 10
+
+// backupsPath returns the directory config backups are kept in
+func backupsPath() string {
+	return filepath.Join(configDir(), "backups")
+}
+
+// encodeYAML encodes v with the two-space indent used by all config files
+func encodeYAML(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeFileAtomic writes data to a temp file next to path, syncs it and
+// renames it over path
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName) // No-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpName, path)
+}
+
+// backupConfig copies the current config.yaml into backups/ unless it is
+// identical to what is about to be written, then prunes old backups
+func backupConfig(next []byte, keep int) error {
+	current, err := os.ReadFile(configFile)
+	if errors.Is(err, os.ErrNotExist) || bytes.Equal(current, next) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(backupsPath(), 0755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("config-%s.yaml", time.Now().Format("20060102-150405.000"))
+	if err := writeFileAtomic(filepath.Join(backupsPath(), name), current); err != nil {
+		return err
+	}
+
+	if keep <= 0 {
+		keep = defaultBackupCount
+	}
+	backups := listConfigBackups()
+	for _, old := range backups[min(keep, len(backups)):] {
+		_ = os.Remove(old)
+	}
+	return nil
+}
+
+// listConfigBackups returns backup files, newest first
+func listConfigBackups() []string {
+	entries, err := os.ReadDir(backupsPath())
+	if err != nil {
+		return nil
+	}
+
+	var backups []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasPrefix(e.Name(), "config-") && strings.HasSuffix(e.Name(), ".yaml") {
+			backups = append(backups, filepath.Join(backupsPath(), e.Name()))
+		}
+	}
+	// Timestamped names sort chronologically
+	sort.Sort(sort.Reverse(sort.StringSlice(backups)))
+	return backups
+}
+
+// writeConfigFile backs up and atomically replaces config.yaml. Layered
+// configs are split back into the files they were read from.
+func writeConfigFile(cfg Config) error {
+	if cfg.Sources != nil {
+		return writeLayeredConfig(cfg)
+	}
+	data, err := encodeYAML(cfg)
+	if err != nil {
+		return fmt.Errorf("could not encode config: %w", err)
+	}
+	return writeConfigData(data, cfg.BackupCount)
+}
+
+// writeConfigData backs up config.yaml and replaces it with data
+func writeConfigData(data []byte, keep int) error {
+	if err := backupConfig(data, keep); err != nil {
+		return fmt.Errorf("could not back up %s: %w", filepath.Base(configFile), err)
+	}
+	if err := writeFileAtomic(configFile, data); err != nil {
+		return fmt.Errorf("could not write %s: %w", configFile, err)
+	}
+	return nil
+}
+
+// writeThemeFile atomically replaces theme.yaml
+func writeThemeFile(theme ThemeConfig) error {
+	data, err := encodeYAML(theme)
+	if err != nil {
+		return fmt.Errorf("could not encode theme: %w", err)
+	}
+	if err := writeFileAtomic(themeFile(), data); err != nil {
+		return fmt.Errorf("could not write %s: %w", themeFile(), err)
+	}
+	return nil
+}
+
+// restoreConfigBackup replaces an unreadable config.yaml with a backup. The
+// broken file is kept as config.yaml.broken-<timestamp> for inspection.
+func restoreConfigBackup(backup string) error {
+	data, err := os.ReadFile(backup)
+	if err != nil {
+		return fmt.Errorf("could not read backup: %w", err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("backup %s is not valid either: %w", filepath.Base(backup), err)
+	}
+
+	if err := setAsideBrokenConfig(); err != nil {
+		return err
+	}
+	return writeFileAtomic(configFile, data)
+}
+
+// setAsideBrokenConfig renames config.yaml so that a fresh one can be written
+func setAsideBrokenConfig() error {
+	if _, err := os.Stat(configFile); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	broken := fmt.Sprintf("%s.broken-%s", configFile, time.Now().Format("20060102-150405"))
+	if err := os.Rename(configFile, broken); err != nil {
+		return fmt.Errorf("could not move broken config aside: %w", err)
+	}
+	return nil
+}
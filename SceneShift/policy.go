@@ -0,0 +1,141 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// --- System Policy ---
+//
+// An optional, administrator-owned policy file that user configuration cannot
+// override. It lives outside the user's config directory so that only an
+// administrator can edit it:
+//
+//	Windows: %ProgramData%\SceneShift\policy.yaml
+//	Other:   /etc/sceneshift/policy.yaml
+
+// Policy holds the system-wide restrictions
+type Policy struct {
+	Protections      []string `yaml:"protections"`
+	ForbiddenActions []string `yaml:"forbidden_actions"` // kill, suspend, resume, restore
+	MaxTargets       int      `yaml:"max_targets"`       // 0 means unlimited
+
+	Path  string `yaml:"-"` // Set when a policy file was found
+	Error string `yaml:"-"` // Set when the policy file could not be read
+}
+
+var policyActions = []string{"kill", "suspend", "resume", "restore"}
+
+// policyPath returns the location of the system policy file
+func policyPath() string {
+	if runtime.GOOS == "windows" {
+		base := os.Getenv("ProgramData")
+		if base == "" {
+			base = `C:\ProgramData`
+		}
+		return filepath.Join(base, "SceneShift", "policy.yaml")
+	}
+	return "/etc/sceneshift/policy.yaml"
+}
+
+// loadPolicy reads the system policy. A policy that exists but cannot be
+// read or parsed fails closed: every action is forbidden until it is fixed.
+func loadPolicy() Policy {
+	path := policyPath()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Policy{}
+	}
+
+	var p Policy
+	if err == nil {
+		err = yaml.Unmarshal(data, &p)
+	}
+	if err != nil {
+		return Policy{
+			ForbiddenActions: policyActions,
+			Path:             path,
+			Error:            fmt.Sprintf("could not load policy %s: %v", path, err),
+		}
+	}
+
+	p.Path = path
+	for i := range p.ForbiddenActions {
+		p.ForbiddenActions[i] = strings.ToLower(strings.TrimSpace(p.ForbiddenActions[i]))
+	}
+	return p
+}
+
+// IsActive reports whether a policy file is in effect
+func (p Policy) IsActive() bool {
+	return p.Path != ""
+}
+
+// Forbids reports whether the policy blocks an action ("kill", "suspend", ...)
+func (p Policy) Forbids(action string) bool {
+	for _, a := range p.ForbiddenActions {
+		if a == action || a == "all" {
+			return true
+		}
+	}
+	return false
+}
+
+// Locks reports whether a process is protected by the policy
+func (p Policy) Locks(processName string) bool {
+	return isInSafelist(processName, p.Protections)
+}
+
+// CheckAction validates an action against the policy before it runs
+func (p Policy) CheckAction(action string, targets int) error {
+	if p.Forbids(action) {
+		return fmt.Errorf("%s is disabled by system policy", strings.ToUpper(action))
+	}
+	if p.MaxTargets > 0 && targets > p.MaxTargets {
+		return fmt.Errorf("system policy allows at most %d apps per action (%d selected)", p.MaxTargets, targets)
+	}
+	return nil
+}
+
+// isProtected reports whether any of an app's process names is covered by the
+// user exclusion list or the system policy
+func (cfg *Config) isProtected(rawNames string) bool {
+	for _, name := range strings.Split(rawNames, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if isInSafelist(name, cfg.Protection.ExclusionList) || cfg.Policy.Locks(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// exclusionRow is a row of the exclusion list manager
+type exclusionRow struct {
+	name   string
+	locked bool
+	index  int // Index into Protection.ExclusionList, -1 for policy-only entries
+}
+
+// exclusionRows lists user exclusions followed by policy protections that
+// the user list does not already contain
+func (cfg *Config) exclusionRows() []exclusionRow {
+	rows := make([]exclusionRow, 0, len(cfg.Protection.ExclusionList)+len(cfg.Policy.Protections))
+	for i, name := range cfg.Protection.ExclusionList {
+		rows = append(rows, exclusionRow{name: name, locked: cfg.Policy.Locks(name), index: i})
+	}
+	for _, name := range cfg.Policy.Protections {
+		if !isInSafelist(name, cfg.Protection.ExclusionList) {
+			rows = append(rows, exclusionRow{name: name, locked: true, index: -1})
+		}
+	}
+	return rows
+}
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"gopkg.in/yaml.v3"
+)
+
+// --- Selective Profile Export ---
+//
+// The export screen picks what goes into a profile: presets bring the apps
+// they switch along with them, and apps, the theme and the protection lists
+// can be added on their own. Profiles are written as JSON or YAML to any
+// path; an existing file is only replaced after confirming.
+
+type exportKind int
+
+const (
+	exportPreset exportKind = iota
+	exportApp
+	exportTheme
+	exportExclusions
+	exportSafeToKill
+)
+
+// exportItem is one entry that can be included in a profile
+type exportItem struct {
+	kind     exportKind
+	name     string
+	selected bool
+}
+
+// startProfileExport opens the export screen with everything selected
+func (m model) startProfileExport() (tea.Model, tea.Cmd) {
+	m.currentState = stateProfileExport
+	m.profileDescription = ""
+	m.profileAuthor = ""
+	m.exportConfirmPath = ""
+	m.exportCursor = 0
+	if m.exportFormat == "" {
+		m.exportFormat = "json"
+	}
+
+	m.exportItems = nil
+	for _, p := range m.config.Presets {
+		m.exportItems = append(m.exportItems, exportItem{kind: exportPreset, name: p.Name, selected: true})
+	}
+	for _, app := range m.config.Apps {
+		m.exportItems = append(m.exportItems, exportItem{kind: exportApp, name: app.Name, selected: true})
+	}
+	m.exportItems = append(m.exportItems,
+		exportItem{kind: exportTheme, name: "Theme", selected: true},
+		exportItem{kind: exportExclusions, name: "Exclusion list", selected: true},
+		exportItem{kind: exportSafeToKill, name: "Safe-to-kill list", selected: true},
+	)
+
+	m.setupProfileInputs()
+	m.inputs[2].SetValue(uniqueProfilePath(filepath.Join(configDir(),
+		"sceneshift-profile-"+time.Now().Format("2006-01-02")+"."+m.exportFormat)))
+	return m, nil
+}
+
+// uniqueProfilePath numbers a default file name so exports made on the same
+// day don't replace each other
+func uniqueProfilePath(path string) string {
+	ext := filepath.Ext(path)
+	stem := strings.TrimSuffix(path, ext)
+	for n := 2; ; n++ {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			return path
+		}
+		path = fmt.Sprintf("%s-%d%s", stem, n, ext)
+	}
+}
+
+// presetOwner returns the first selected preset that switches an app
+func (m model) presetOwner(app string) string {
+	for _, item := range m.exportItems {
+		if item.kind != exportPreset || !item.selected {
+			continue
+		}
+		if i := findPreset(m.config.Presets, item.name); i >= 0 && containsFold(m.config.Presets[i].Apps, app) {
+			return item.name
+		}
+	}
+	return ""
+}
+
+// exportIncludes reports whether an item ends up in the profile
+func (m model) exportIncludes(item exportItem) bool {
+	return item.selected || (item.kind == exportApp && m.presetOwner(item.name) != "")
+}
+
+func (m model) exportIncluded(kind exportKind) bool {
+	for _, item := range m.exportItems {
+		if item.kind == kind && m.exportIncludes(item) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildExportProfile collects the selected entries into a profile
+func (m model) buildExportProfile(description, author string) ConfigProfile {
+	profile := ConfigProfile{
+		Metadata: ProfileMetadata{
+			Version:           currentProfileFormat,
+			SceneShiftVersion: Version,
+			ExportDate:        time.Now(),
+			Description:       description,
+			Author:            author,
+		},
+		Apps:    []AppEntry{},
+		Presets: []PresetConfig{},
+	}
+
+	for _, item := range m.exportItems {
+		if !m.exportIncludes(item) {
+			continue
+		}
+		switch item.kind {
+		case exportPreset:
+			if i := findPreset(m.config.Presets, item.name); i >= 0 {
+				profile.Presets = append(profile.Presets, m.config.Presets[i])
+			}
+		case exportApp:
+			if i := findApp(m.config.Apps, item.name); i >= 0 {
+				app := m.config.Apps[i]
+				if m.exportVariables {
+					app = m.config.contractApp(app)
+				}
+				profile.Apps = append(profile.Apps, app)
+			}
+		case exportTheme:
+			profile.Theme = savedTheme(m.config.Theme)
+		case exportExclusions:
+			profile.Protection = m.config.Protection
+		case exportSafeToKill:
+			profile.SafeToKill = m.config.SafeToKill
+		}
+	}
+	return profile
+}
+
+// encodeProfile writes a profile as JSON or YAML
+func encodeProfile(profile ConfigProfile, format string) ([]byte, error) {
+	if format == "yaml" {
+		return encodeYAML(profile)
+	}
+	return json.MarshalIndent(profile, "", "  ")
+}
+
+// exportProfilePath resolves the file name typed on the export screen
+func (m model) exportProfilePath() string {
+	path := strings.Trim(strings.TrimSpace(m.inputs[2].Value()), `"`)
+	if path == "" {
+		path = "sceneshift-profile-" + time.Now().Format("2006-01-02")
+	}
+	path = m.config.expand(path)
+	if filepath.Ext(path) == "" {
+		path += "." + m.exportFormat
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(configDir(), path)
+	}
+	return path
+}
+
+// exportProfile writes the selected entries to the chosen file
+func (m *model) exportProfile(description, author string) error {
+	path := m.exportProfilePath()
+	data, err := encodeProfile(m.buildExportProfile(description, author), m.exportFormat)
+	if err != nil {
+		return fmt.Errorf("failed to marshal profile: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write file: %v", err)
+	}
+
+	m.profileMessage = fmt.Sprintf(glyphs("✅ Profile exported to: %s"), path)
+	return nil
+}
+
+// setExportFormat switches between JSON and YAML, updating the extension of
+// the file name
+func (m *model) setExportFormat(format string) {
+	m.exportFormat = format
+	path := m.inputs[2].Value()
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".yaml", ".yml":
+		m.inputs[2].SetValue(strings.TrimSuffix(path, filepath.Ext(path)) + "." + format)
+	}
+	m.exportConfirmPath = ""
+}
+
+func (m model) updateProfileExport(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	onList := m.focusIndex == len(m.inputs)
+
+	switch msg.String() {
+	case "enter":
+		description := m.inputs[0].Value()
+		if description == "" {
+			description = "SceneShift configuration"
+		}
+		path := m.exportProfilePath()
+		if _, err := os.Stat(path); err == nil && m.exportConfirmPath != path {
+			// Ask before replacing an earlier export
+			m.exportConfirmPath = path
+			return m, nil
+		}
+		if err := m.exportProfile(description, m.inputs[1].Value()); err != nil {
+			m.profileMessage = fmt.Sprintf(glyphs("❌ Export failed: %v"), err)
+		}
+		m.currentState = stateMenu
+		return m, nil
+
+	case "esc":
+		m.currentState = stateMenu
+		return m, nil
+
+	case "ctrl+r":
+		m.exportVariables = !m.exportVariables
+		return m, nil
+
+	case "ctrl+t":
+		if m.exportFormat == "yaml" {
+			m.setExportFormat("json")
+		} else {
+			m.setExportFormat("yaml")
+		}
+		return m, nil
+
+	case "tab", "shift+tab":
+		// The selection list is the last stop after the inputs
+		stops := len(m.inputs) + 1
+		if msg.String() == "tab" {
+			m.focusIndex = (m.focusIndex + 1) % stops
+		} else {
+			m.focusIndex = (m.focusIndex - 1 + stops) % stops
+		}
+		for i := range m.inputs {
+			if i == m.focusIndex {
+				m.inputs[i].Focus()
+			} else {
+				m.inputs[i].Blur()
+			}
+		}
+		return m, nil
+	}
+
+	if onList {
+		switch {
+		case key.Matches(msg, m.keys.Up):
+			if m.exportCursor > 0 {
+				m.exportCursor--
+			}
+		case key.Matches(msg, m.keys.Down):
+			if m.exportCursor < len(m.exportItems)-1 {
+				m.exportCursor++
+			}
+		case key.Matches(msg, m.keys.Toggle):
+			m.exportItems[m.exportCursor].selected = !m.exportItems[m.exportCursor].selected
+		case key.Matches(msg, m.keys.SelectAll), key.Matches(msg, m.keys.DeselectAll):
+			all := key.Matches(msg, m.keys.SelectAll)
+			for i := range m.exportItems {
+				m.exportItems[i].selected = all
+			}
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.inputs[m.focusIndex], cmd = m.inputs[m.focusIndex].Update(msg)
+	if m.focusIndex == 2 {
+		m.exportConfirmPath = ""
+	}
+	return m, cmd
+}
+
+func (m model) viewProfileExport() string {
+	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Select)).Bold(true).Underline(true)
+	inputStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Highlight)).MarginBottom(1)
+	base := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Text))
+	selected := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Select)).Bold(true)
+	unselected := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Text)).Faint(true)
+	warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Warn))
+
+	var s string
+	s += titleStyle.Render(glyphs("💾 EXPORT PROFILE")) + "\n\n"
+	s += base.Render("Choose what to share. Presets bring the apps they switch.") + "\n\n"
+
+	for i := range m.inputs {
+		s += inputStyle.Render(m.inputs[i].View()) + "\n"
+	}
+
+	checkbox := func(on bool) string {
+		if on {
+			return "[x]"
+		}
+		return "[ ]"
+	}
+	s += base.Render(fmt.Sprintf(glyphs("Format: %s • %s Rewrite paths as variables (e.g. ${LOCALAPPDATA})"),
+		strings.ToUpper(m.exportFormat), checkbox(m.exportVariables))) + "\n\n"
+
+	// Keep the cursor in view on short terminals
+	rows := max(m.height-22, 5)
+	start := 0
+	if m.exportCursor >= rows {
+		start = m.exportCursor - rows + 1
+	}
+	end := min(start+rows, len(m.exportItems))
+
+	headings := map[exportKind]string{exportPreset: "PRESETS", exportApp: "APPS", exportTheme: "SETTINGS"}
+	onList := m.focusIndex == len(m.inputs)
+	for i := start; i < end; i++ {
+		item := m.exportItems[i]
+		if h, ok := headings[item.kind]; ok && (i == start || m.exportItems[i-1].kind != item.kind) {
+			s += unselected.Render(h) + "\n"
+		}
+
+		line := checkbox(m.exportIncludes(item)) + " " + item.name
+		if item.kind == exportApp && !item.selected {
+			if owner := m.presetOwner(item.name); owner != "" {
+				line += " (used by " + owner + ")"
+			}
+		}
+		if onList && i == m.exportCursor {
+			s += selected.Render("> "+line) + "\n"
+		} else {
+			s += unselected.Render("  "+line) + "\n"
+		}
+	}
+	if !m.exportIncluded(exportPreset) && !m.exportIncluded(exportApp) {
+		s += "\n" + warnStyle.Render("No presets or apps selected.") + "\n"
+	}
+
+	if m.exportConfirmPath != "" {
+		s += "\n" + warnStyle.Render(glyphs("⚠️ ")+m.exportConfirmPath+" already exists. Press Enter again to replace it.") + "\n"
+	}
+
+	s += "\n" + lipgloss.NewStyle().Faint(true).Render(glyphs("Tab: Next field/list • ")+hint(m.keys.Toggle)+glyphs(": Include • ")+hint(m.keys.SelectAll)+"/"+hint(m.keys.DeselectAll)+glyphs(": All/none • Ctrl+T: JSON/YAML • Ctrl+R: Variables • Enter: Export • Esc: Cancel")) + "\n"
+	return s
+}
+
+// isProfileFile reports whether a file in the config directory is an
+// exported profile, as opposed to config.yaml, theme.yaml or a pack
+func isProfileFile(name string, data []byte) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json", ".yaml", ".yml":
+	default:
+		return false
+	}
+	if strings.HasPrefix(name, "sceneshift-profile-") {
+		return true
+	}
+	var probe struct {
+		Metadata *ProfileMetadata `json:"metadata" yaml:"metadata"`
+	}
+	if json.Unmarshal(data, &probe) != nil {
+		probe.Metadata = nil
+		if yaml.Unmarshal(data, &probe) != nil {
+			return false
+		}
+	}
+	return probe.Metadata != nil && !probe.Metadata.ExportDate.IsZero()
+}
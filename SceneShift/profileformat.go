@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// --- Profile Format Versions ---
+//
+// metadata.version is the format of a profile file, separate from the
+// SceneShift version that wrote it:
+//
+//	1.0  v2.2  Go field names (ExecPath, ProcessName, ...), runtime PIDs included
+//	2.0  v2.3  snake_case keys matching config.yaml, launch args
+//
+// Older majors are upgraded one step at a time, like config.yaml schema
+// migrations. A newer minor of the current major only adds fields and is
+// read as is; a newer major is refused.
+
+const currentProfileFormat = "2.0"
+
+type profileMigration struct {
+	from  int // Major format version the step upgrades from
+	name  string
+	apply func(raw map[string]interface{}) error
+}
+
+var profileMigrations = []profileMigration{
+	{from: 1, name: "1.x → 2.0", apply: migrateProfileV1ToV2},
+}
+
+// decodeProfile parses a JSON or YAML profile, upgrading older formats. It
+// returns the format the file was written in.
+func decodeProfile(data []byte) (ConfigProfile, string, error) {
+	var profile ConfigProfile
+	raw := map[string]interface{}{}
+	var err error
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "{") {
+		err = json.Unmarshal(data, &raw)
+	} else {
+		err = yaml.Unmarshal(data, &raw)
+	}
+	if err != nil {
+		return profile, "", fmt.Errorf("invalid profile format: %v", err)
+	}
+
+	meta, _ := raw["metadata"].(map[string]interface{})
+	if meta == nil {
+		meta = map[string]interface{}{}
+		raw["metadata"] = meta
+	}
+	var format string
+	switch version := meta["version"].(type) {
+	case nil:
+	case string:
+		format = version
+	default:
+		format = fmt.Sprint(version) // Unquoted, e.g. version: 2.0 or "version": 3
+		meta["version"] = format
+	}
+	if format == "" {
+		format = "1.0" // Written before the field was checked
+	}
+	v, err := parseSemver(format)
+	if err != nil {
+		return profile, format, fmt.Errorf("invalid profile format version %q", format)
+	}
+	current, _ := parseSemver(currentProfileFormat)
+	if v.major > current.major {
+		return profile, format, fmt.Errorf("profile format %s needs a newer SceneShift (this one reads up to %d.x); written by %s",
+			format, current.major, profileWriter(meta))
+	}
+
+	for major := v.major; major < current.major; major++ {
+		step := findProfileMigration(major)
+		if step == nil {
+			return profile, format, fmt.Errorf("no upgrade from profile format %d.x", major)
+		}
+		if err := step.apply(raw); err != nil {
+			return profile, format, fmt.Errorf("upgrading profile %s: %w", glyphs(step.name), err)
+		}
+	}
+	if v.major < current.major {
+		meta["version"] = currentProfileFormat
+	}
+
+	upgraded, err := json.Marshal(raw)
+	if err != nil {
+		return profile, format, err
+	}
+	if err := json.Unmarshal(upgraded, &profile); err != nil {
+		return profile, format, fmt.Errorf("invalid profile format: %v", err)
+	}
+	return profile, format, nil
+}
+
+func findProfileMigration(major int) *profileMigration {
+	for i := range profileMigrations {
+		if profileMigrations[i].from == major {
+			return &profileMigrations[i]
+		}
+	}
+	return nil
+}
+
+func profileWriter(meta map[string]interface{}) string {
+	if v, ok := meta["sceneshift_version"].(string); ok && v != "" {
+		return "v" + v
+	}
+	return "an unknown version"
+}
+
+// migrateProfileV1ToV2 renames the Go field names of 1.x profiles to the
+// snake_case keys used by config.yaml and drops runtime PIDs
+func migrateProfileV1ToV2(raw map[string]interface{}) error {
+	renameList := func(key string) error {
+		v, ok := raw[key]
+		if !ok || v == nil {
+			return nil
+		}
+		items, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s is not a list", key)
+		}
+		for _, item := range items {
+			entry, ok := item.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("%s contains a non-object entry", key)
+			}
+			delete(entry, "PIDs")
+			snakeCaseKeys(entry)
+		}
+		return nil
+	}
+	if err := renameList("apps"); err != nil {
+		return err
+	}
+	if err := renameList("presets"); err != nil {
+		return err
+	}
+	for _, key := range []string{"theme", "protection", "safe_to_kill"} {
+		if entry, ok := raw[key].(map[string]interface{}); ok {
+			snakeCaseKeys(entry)
+		}
+	}
+	return nil
+}
+
+// snakeCaseKeys renames ExecPath-style keys to exec_path in place
+func snakeCaseKeys(m map[string]interface{}) {
+	for k, v := range m {
+		if s := snakeCase(k); s != k {
+			delete(m, k)
+			m[s] = v
+		}
+	}
+}
+
+func snakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeProfileVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		format  string
+		refused bool
+	}{
+		{name: "quoted yaml", data: "metadata:\n  version: \"2.0\"\n", format: "2.0"},
+		{name: "unquoted yaml", data: "metadata:\n  version: 2.0\n", format: "2"},
+		{name: "unquoted yaml minor", data: "metadata:\n  version: 2.1\n", format: "2.1"},
+		{name: "missing", data: "", format: "1.0"},
+		{name: "unquoted yaml newer major", data: "metadata:\n  version: 3\n", refused: true},
+		{name: "unquoted json newer major", data: `{"metadata": {"version": 3}}`, refused: true},
+		{name: "unquoted json float newer major", data: `{"metadata": {"version": 3.0}}`, refused: true},
+		{name: "not a version", data: "metadata:\n  version: true\n", refused: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, format, err := decodeProfile([]byte(tt.data))
+			if tt.refused {
+				if err == nil {
+					t.Fatalf("decoded format %q, want an error", format)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeProfile: %v", err)
+			}
+			if format != tt.format {
+				t.Errorf("format = %q, want %q", format, tt.format)
+			}
+		})
+	}
+
+	_, _, err := decodeProfile([]byte(`{"metadata": {"version": 3}}`))
+	if err == nil || !strings.Contains(err.Error(), "newer SceneShift") {
+		t.Errorf("version 3 error = %v, want a newer SceneShift", err)
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// --- Semantic Versions ---
+//
+// Versions are compared by their numeric parts, so 2.10.0 is newer than
+// 2.9.0. A leading "v" and missing parts are accepted ("v2.1" is 2.1.0), as
+// profile formats are written as "major.minor". Pre-releases sort before
+// their release and build metadata is ignored, as in semver.org.
+
+type semver struct {
+	major, minor, patch int
+	pre                 []string // Pre-release identifiers, e.g. ["beta", "2"]
+}
+
+func parseSemver(s string) (semver, error) {
+	var v semver
+	core := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexByte(core, '+'); i >= 0 {
+		core = core[:i]
+	}
+	if i := strings.IndexByte(core, '-'); i >= 0 {
+		v.pre = strings.Split(core[i+1:], ".")
+		core = core[:i]
+	}
+
+	parts := strings.Split(core, ".")
+	if core == "" || len(parts) > 3 {
+		return v, fmt.Errorf("invalid version %q", s)
+	}
+	nums := []*int{&v.major, &v.minor, &v.patch}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("invalid version %q", s)
+		}
+		*nums[i] = n
+	}
+	for _, id := range v.pre {
+		if id == "" {
+			return v, fmt.Errorf("invalid version %q", s)
+		}
+	}
+	return v, nil
+}
+
+func (v semver) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.major, v.minor, v.patch)
+	if len(v.pre) > 0 {
+		s += "-" + strings.Join(v.pre, ".")
+	}
+	return s
+}
+
+// compare returns -1, 0 or 1 as v is older than, equal to or newer than o
+func (v semver) compare(o semver) int {
+	for _, d := range []int{v.major - o.major, v.minor - o.minor, v.patch - o.patch} {
+		if d != 0 {
+			return sign(d)
+		}
+	}
+
+	// A release is newer than any of its pre-releases
+	switch {
+	case len(v.pre) == 0 && len(o.pre) == 0:
+		return 0
+	case len(v.pre) == 0:
+		return 1
+	case len(o.pre) == 0:
+		return -1
+	}
+	for i := 0; i < len(v.pre) && i < len(o.pre); i++ {
+		if c := comparePreID(v.pre[i], o.pre[i]); c != 0 {
+			return c
+		}
+	}
+	return sign(len(v.pre) - len(o.pre))
+}
+
+// comparePreID orders numeric identifiers numerically and below text ones
+func comparePreID(a, b string) int {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+	switch {
+	case errA == nil && errB == nil:
+		return sign(na - nb)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+// newerThanRunning reports whether version is newer than this build. Builds
+// with an unparseable Version (e.g. "dev") never report newer.
+func newerThanRunning(version string) bool {
+	v, err := parseSemver(version)
+	if err != nil {
+		return false
+	}
+	running, err := parseSemver(Version)
+	if err != nil {
+		return false
+	}
+	return v.compare(running) > 0
+}
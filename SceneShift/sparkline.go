@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// --- Resource History ---
+//
+// Every refresh appends each app's CPU and RAM to a rolling series covering
+// the last few minutes, so a spike shows up even if it is over by the time
+// you look. The series feed the Trend column of the app table and the charts
+// in the app detail view. History lives in memory only and is keyed by app
+// name, so renaming an app starts it afresh.
+
+const statsHistoryWindow = 5 * time.Minute
+
+// statsSample is one refresh of one app
+type statsSample struct {
+	at    time.Time
+	cpu   float64
+	ramMB uint64
+}
+
+// recordStats appends a refresh to the history, dropping samples older than
+// the window and apps that are no longer configured
+func (m *model) recordStats(apps map[string]appStats, at time.Time) {
+	if m.statsHistory == nil {
+		m.statsHistory = map[string][]statsSample{}
+	}
+	cutoff := at.Add(-statsHistoryWindow)
+	for name := range m.statsHistory {
+		if _, ok := apps[name]; !ok {
+			delete(m.statsHistory, name)
+		}
+	}
+	for name, stats := range apps {
+		series := m.statsHistory[name]
+		i := 0
+		for i < len(series) && series[i].at.Before(cutoff) {
+			i++
+		}
+		m.statsHistory[name] = append(series[i:], statsSample{at: at, cpu: stats.CPUPercent, ramMB: stats.RAMMB})
+	}
+}
+
+// peak returns the largest value, 0 for none
+func peak(values []float64) float64 {
+	var max float64
+	for _, v := range values {
+		max = math.Max(max, v)
+	}
+	return max
+}
+
+func average(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// seriesCPU and seriesRAM extract one figure from a series for plotting
+func seriesCPU(series []statsSample) []float64 {
+	values := make([]float64, len(series))
+	for i, s := range series {
+		values[i] = s.cpu
+	}
+	return values
+}
+
+func seriesRAM(series []statsSample) []float64 {
+	values := make([]float64, len(series))
+	for i, s := range series {
+		values[i] = float64(s.ramMB)
+	}
+	return values
+}
+
+// --- Rendering ---
+
+// sparkGlyphs are the heights of a bar, from empty to full
+const sparkGlyphs = " ▁▂▃▄▅▆▇█"
+
+// sparkline renders the last width values as one row of blocks, scaled to
+// max (or to the largest value if that is higher). Missing history is left
+// blank on the left.
+func sparkline(values []float64, width int, max float64) string {
+	if width <= 0 {
+		return ""
+	}
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	for _, v := range values {
+		max = math.Max(max, v)
+	}
+	sparkBlocks := []rune(glyphs(sparkGlyphs))
+	var b strings.Builder
+	b.WriteString(strings.Repeat(" ", width-len(values)))
+	for _, v := range values {
+		level := 0
+		if max > 0 && v > 0 {
+			// Anything above zero gets at least the lowest block
+			level = int(math.Ceil(v / max * float64(len(sparkBlocks)-1)))
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
+
+// barChart renders values as columns height rows tall with an axis labelled
+// by format, e.g. for the CPU and RAM charts of the detail view
+func barChart(values []float64, width, height int, max float64, format func(float64) string) string {
+	if width <= 0 || height <= 0 {
+		return ""
+	}
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	for _, v := range values {
+		max = math.Max(max, v)
+	}
+	top, bottom := format(max), format(0)
+	labelWidth := len(top)
+	if len(bottom) > labelWidth {
+		labelWidth = len(bottom)
+	}
+
+	sparkBlocks := []rune(glyphs(sparkGlyphs))
+	steps := len(sparkBlocks) - 1
+	var s string
+	for row := 0; row < height; row++ {
+		label := ""
+		switch row {
+		case 0:
+			label = top
+		case height - 1:
+			label = bottom
+		}
+		line := fmt.Sprintf(glyphs("%*s ┤"), labelWidth, label)
+		line += strings.Repeat(" ", width-len(values))
+		// Units of one eighth of a row still to fill below this row's top
+		floor := (height - 1 - row) * steps
+		for _, v := range values {
+			units := 0
+			if max > 0 {
+				units = int(math.Round(v / max * float64(height*steps)))
+			}
+			fill := units - floor
+			if fill < 0 {
+				fill = 0
+			}
+			if fill > steps {
+				fill = steps
+			}
+			line += string(sparkBlocks[fill])
+		}
+		s += line + "\n"
+	}
+	return s
+}
@@ -0,0 +1,391 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"gopkg.in/yaml.v3"
+)
+
+// --- Config Validation ---
+//
+// validateConfigFiles checks config.yaml and theme.yaml for mistakes that
+// would otherwise be silently accepted, reporting each with the YAML line
+// and column it was found at. It backs both `sceneshift config validate`
+// and the diagnostics panel shown at startup.
+
+// Diagnostic is a single problem found in a config file
+type Diagnostic struct {
+	Severity string // "error" or "warning"
+	File     string
+	Line     int
+	Column   int
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	if d.Line > 0 && d.Column > 0 {
+		return fmt.Sprintf("%s:%d:%d: %s: %s", d.File, d.Line, d.Column, d.Severity, d.Message)
+	}
+	if d.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s: %s", d.File, d.Line, d.Severity, d.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", d.File, d.Severity, d.Message)
+}
+
+var (
+	yamlLinePattern     = regexp.MustCompile(`line (\d+)`)
+	unknownFieldPattern = regexp.MustCompile(`^field (\S+) not found in type \S+$`)
+	hexColorPattern     = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+)
+
+// diagnosticsCollector accumulates diagnostics for one file
+type diagnosticsCollector struct {
+	file  string
+	diags []Diagnostic
+}
+
+func (dc *diagnosticsCollector) add(severity string, node *yaml.Node, format string, args ...interface{}) {
+	d := Diagnostic{Severity: severity, File: dc.file, Message: fmt.Sprintf(format, args...)}
+	if node != nil {
+		d.Line, d.Column = node.Line, node.Column
+	}
+	dc.diags = append(dc.diags, d)
+}
+
+// addYAMLError records a decoder error, recovering its line number
+func (dc *diagnosticsCollector) addYAMLError(severity string, err error) {
+	var typeErr *yaml.TypeError
+	msgs := []string{err.Error()}
+	if errors.As(err, &typeErr) {
+		msgs = typeErr.Errors
+	}
+	for _, msg := range msgs {
+		d := Diagnostic{Severity: severity, File: dc.file, Message: strings.TrimPrefix(msg, "yaml: ")}
+		if match := yamlLinePattern.FindStringSubmatch(msg); match != nil {
+			d.Line, _ = strconv.Atoi(match[1])
+			d.Message = strings.TrimSpace(yamlLinePattern.ReplaceAllString(d.Message, ""))
+			d.Message = strings.TrimPrefix(d.Message, ": ")
+		}
+		if match := unknownFieldPattern.FindStringSubmatch(d.Message); match != nil {
+			d.Message = fmt.Sprintf("unknown field %q", match[1])
+		}
+		dc.diags = append(dc.diags, d)
+	}
+}
+
+// mappingValue returns the key and value nodes for a field of a mapping node
+func mappingValue(node *yaml.Node, field string) (*yaml.Node, *yaml.Node) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == field {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// sequenceItems returns the items of a sequence node
+func sequenceItems(node *yaml.Node) []*yaml.Node {
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return node.Content
+}
+
+// validateConfigFiles validates config.yaml and theme.yaml on disk
+func validateConfigFiles() []Diagnostic {
+	var diags []Diagnostic
+
+	data, err := os.ReadFile(configFile)
+	if errors.Is(err, os.ErrNotExist) {
+		diags = append(diags, Diagnostic{Severity: "warning", File: configFile, Message: "file does not exist; defaults will be created on first launch"})
+	} else if err != nil {
+		diags = append(diags, Diagnostic{Severity: "error", File: configFile, Message: err.Error()})
+	} else {
+		diags = append(diags, validateConfigData(configFile, data)...)
+	}
+
+	if data, err := os.ReadFile(themeFile()); err == nil {
+		diags = append(diags, validateThemeData(themeFile(), data)...)
+	}
+	return diags
+}
+
+// validateConfigData validates the contents of a config.yaml
+func validateConfigData(file string, data []byte) []Diagnostic {
+	dc := &diagnosticsCollector{file: file}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		dc.addYAMLError("error", err)
+		return dc.diags
+	}
+	if len(doc.Content) == 0 {
+		return dc.diags
+	}
+	root := doc.Content[0]
+
+	cfg, _, err := migrateConfig(data)
+	if err != nil {
+		dc.addYAMLError("error", err)
+		return dc.diags
+	}
+
+	// Unknown fields usually mean a typo, e.g. exec_pth. Old formats are
+	// migrated on load, so only check configs already on the current schema.
+	if cfg.SchemaVersion == currentSchemaVersion {
+		if version, _ := mappingValue(root, "schema_version"); version != nil {
+			decoder := yaml.NewDecoder(bytes.NewReader(data))
+			decoder.KnownFields(true)
+			var strict Config
+			if err := decoder.Decode(&strict); err != nil {
+				dc.addYAMLError("warning", err)
+			}
+		}
+	}
+
+	// Presets and hotkeys may rely on apps and keys from included files
+	merged := cfg
+	if cfg.hasLayers() {
+		if merged, err = resolveLayers(cfg); err != nil {
+			dc.add("error", nil, "%v", err)
+			merged = cfg
+		}
+	}
+	cfg.Hotkeys = merged.Hotkeys
+	cfg.Variables = merged.Variables
+
+	validateApps(dc, root, cfg, merged.Apps)
+	validatePresets(dc, root, cfg, merged.Apps)
+	validateHotkeys(dc, root, cfg)
+
+	if cfg.RefreshInterval < 0 || (cfg.RefreshInterval > 0 && cfg.RefreshInterval < minRefreshInterval.Seconds()) {
+		_, node := mappingValue(root, "refresh_interval")
+		dc.add("warning", node, "refresh_interval %v is below the minimum; %v is used", cfg.RefreshInterval, cfg.refreshInterval())
+	}
+
+	return dc.diags
+}
+
+func validateApps(dc *diagnosticsCollector, root *yaml.Node, cfg Config, merged []AppEntry) {
+	_, appsNode := mappingValue(root, "apps")
+	items := sequenceItems(appsNode)
+
+	seen := map[string]*yaml.Node{}
+	for i, app := range cfg.Apps {
+		var node *yaml.Node
+		if i < len(items) {
+			node = items[i]
+		}
+
+		_, nameNode := mappingValue(node, "name")
+		if strings.TrimSpace(app.Name) == "" {
+			dc.add("error", node, "app %d has no name", i+1)
+		} else if prev, ok := seen[strings.ToLower(app.Name)]; ok {
+			dc.add("warning", nameNode, "duplicate app name %q (first defined on line %d)", app.Name, prev.Line)
+		} else if nameNode != nil {
+			seen[strings.ToLower(app.Name)] = nameNode
+		}
+
+		// An app may only override fields of one defined in an included file
+		if i := findApp(merged, app.Name); i >= 0 {
+			app.ProcessName = merged[i].ProcessName
+		}
+		if strings.TrimSpace(app.ProcessName) == "" {
+			dc.add("error", node, "app %q has no process_name", app.Name)
+		}
+
+		if app.ExecPath != "" {
+			_, pathNode := mappingValue(node, "exec_path")
+			if missing := cfg.unresolvedVars(app.ExecPath); len(missing) > 0 {
+				dc.add("warning", pathNode, "exec_path for %q uses unknown variable %s", app.Name, strings.Join(missing, ", "))
+			} else if _, err := os.Stat(cfg.expand(app.ExecPath)); err != nil {
+				dc.add("warning", pathNode, "exec_path for %q does not exist: %s", app.Name, cfg.expand(app.ExecPath))
+			}
+		}
+	}
+}
+
+func validatePresets(dc *diagnosticsCollector, root *yaml.Node, cfg Config, apps []AppEntry) {
+	_, presetsNode := mappingValue(root, "presets")
+	items := sequenceItems(presetsNode)
+
+	appNames := map[string]bool{}
+	for _, app := range apps {
+		appNames[strings.ToLower(app.Name)] = true
+	}
+
+	seenKeys := map[string]*yaml.Node{}
+	for i, preset := range cfg.Presets {
+		var node *yaml.Node
+		if i < len(items) {
+			node = items[i]
+		}
+
+		_, keyNode := mappingValue(node, "key")
+		switch {
+		case preset.Key == "":
+			dc.add("warning", node, "preset %q has no key and can only be applied from the preset list", preset.Name)
+		case seenKeys[preset.Key] != nil:
+			dc.add("error", keyNode, "preset key %q is already used by another preset (line %d)", preset.Key, seenKeys[preset.Key].Line)
+		default:
+			if keyNode != nil {
+				seenKeys[preset.Key] = keyNode
+			}
+		}
+
+		_, appsNode := mappingValue(node, "apps")
+		appItems := sequenceItems(appsNode)
+		for j, name := range preset.Apps {
+			if appNames[strings.ToLower(name)] {
+				continue
+			}
+			var appNode *yaml.Node
+			if j < len(appItems) {
+				appNode = appItems[j]
+			} else {
+				appNode = appsNode
+			}
+			dc.add("warning", appNode, "preset %q references unknown app %q", preset.Name, name)
+		}
+	}
+}
+
+// validateHotkeys reports hotkeys bound to more than one menu action,
+// including preset keys that would shadow a built-in binding
+func validateHotkeys(dc *diagnosticsCollector, root *yaml.Node, cfg Config) {
+	owners := map[string]string{}
+	for _, b := range menuBindings(newKeyMap(cfg.Hotkeys)) {
+		for _, k := range b.binding.Keys() {
+			if prev, ok := owners[k]; ok && prev != b.name {
+				dc.add("error", hotkeyNode(root, b.name), "key %q is bound to both %s and %s", displayKey(k), prev, b.name)
+				continue
+			}
+			owners[k] = b.name
+		}
+	}
+
+	_, presetsNode := mappingValue(root, "presets")
+	items := sequenceItems(presetsNode)
+	for i, preset := range cfg.Presets {
+		if owner, ok := owners[preset.Key]; ok && preset.Key != "" {
+			var keyNode *yaml.Node
+			if i < len(items) {
+				_, keyNode = mappingValue(items[i], "key")
+			}
+			dc.add("error", keyNode, "preset %q key %q collides with the built-in %s binding", preset.Name, displayKey(preset.Key), owner)
+		}
+	}
+}
+
+// hotkeyNode locates a hotkeys entry by its binding name, if configured
+func hotkeyNode(root *yaml.Node, name string) *yaml.Node {
+	_, hotkeys := mappingValue(root, "hotkeys")
+	_, node := mappingValue(hotkeys, name)
+	return node
+}
+
+func displayKey(k string) string {
+	if k == " " {
+		return "space"
+	}
+	return k
+}
+
+type namedBinding struct {
+	name    string
+	binding key.Binding
+}
+
+// menuBindings lists the bindings active on the main menu, named after
+// their hotkeys config field
+func menuBindings(k keyMap) []namedBinding {
+	return []namedBinding{
+		{"up", k.Up}, {"down", k.Down}, {"toggle", k.Toggle},
+		{"select_all", k.SelectAll}, {"deselect_all", k.DeselectAll},
+		{"kill_mode", k.Kill}, {"suspend_mode", k.Suspend},
+		{"resume_mode", k.Resume}, {"restore_mode", k.Restore},
+		{"quit", k.Quit}, {"help", k.Help},
+		{"new_item", k.NewItem}, {"edit_item", k.EditItem}, {"delete_item", k.DeleteItem},
+		{"theme_menu", k.ThemeMenu}, {"preset_menu", k.PresetMenu},
+		{"safelist_menu", k.SafelistMenu}, {"history", k.History},
+		{"undo", k.Undo}, {"export", k.Export}, {"import", k.Import},
+		{"repair_paths", k.RepairPaths}, {"details", k.Details},
+		{"sort", k.Sort}, {"sort_reverse", k.SortReverse}, {"filter", k.Filter},
+		{"process_explorer", k.Explorer}, {"command_palette", k.Palette},
+	}
+}
+
+// validateThemeData checks that every theme color is a valid hex code
+func validateThemeData(file string, data []byte) []Diagnostic {
+	dc := &diagnosticsCollector{file: file}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		dc.addYAMLError("error", err)
+		return dc.diags
+	}
+	if len(doc.Content) == 0 {
+		return dc.diags
+	}
+	root := doc.Content[0]
+
+	fields := []string{"base", "surface", "text", "highlight", "select", "kill", "restore", "suspend", "warn"}
+	for _, field := range fields {
+		_, node := mappingValue(root, field)
+		if node == nil {
+			continue
+		}
+		if !hexColorPattern.MatchString(node.Value) {
+			dc.add("error", node, "%s color %q is not a hex code like #1e1e2e", field, node.Value)
+		}
+	}
+	return dc.diags
+}
+
+// runConfigCommand implements `sceneshift config <subcommand>`
+func runConfigCommand(args []string) int {
+	if len(args) == 0 || args[0] != "validate" {
+		fmt.Println("Usage: SceneShift.exe config validate [--config <file>]")
+		return 2
+	}
+
+	diags := validateConfigFiles()
+	sort.SliceStable(diags, func(i, j int) bool {
+		if diags[i].File != diags[j].File {
+			return diags[i].File < diags[j].File
+		}
+		if diags[i].Line != diags[j].Line {
+			return diags[i].Line < diags[j].Line
+		}
+		return diags[i].Column < diags[j].Column
+	})
+
+	if len(diags) == 0 {
+		fmt.Printf("✅ %s is valid\n", configFile)
+		return 0
+	}
+	errCount := 0
+	for _, d := range diags {
+		fmt.Println(d.String())
+		if d.Severity == "error" {
+			errCount++
+		}
+	}
+	fmt.Printf("\n%d error(s), %d warning(s) in %s\n", errCount, len(diags)-errCount, filepath.Dir(configFile))
+	if errCount > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// --- Path Variables ---
+//
+// exec_path, args and process_name may refer to variables so that the same
+// entry works for every user and machine:
+//
+//	exec_path: ${LOCALAPPDATA}\Discord\Update.exe
+//	exec_path: %LOCALAPPDATA%\Discord\Update.exe
+//	exec_path: ~/Applications/Discord.app
+//
+// Names are looked up in the variables: section of config.yaml, then the
+// environment, then the built-ins HOME and CONFIG_DIR. Values are expanded
+// when they are used, so config.yaml keeps the variable form. Unknown
+// variables are left as they are.
+
+var (
+	bracedVarPattern  = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_()]*)\}`)
+	percentVarPattern = regexp.MustCompile(`%([A-Za-z_][A-Za-z0-9_()]*)%`)
+)
+
+// maxVarDepth limits custom variables that refer to other variables
+const maxVarDepth = 5
+
+// lookupVar resolves a variable name
+func (cfg *Config) lookupVar(name string, depth int) (string, bool) {
+	for k, v := range cfg.Variables {
+		if strings.EqualFold(k, name) {
+			if depth < maxVarDepth {
+				v = cfg.expandDepth(v, depth+1)
+			}
+			return v, true
+		}
+	}
+	if v, ok := os.LookupEnv(name); ok {
+		return v, true
+	}
+	switch strings.ToUpper(name) {
+	case "HOME":
+		if home, err := os.UserHomeDir(); err == nil {
+			return home, true
+		}
+	case "CONFIG_DIR":
+		return configDir(), true
+	}
+	return "", false
+}
+
+// expand replaces variables and a leading ~ in s
+func (cfg *Config) expand(s string) string {
+	return cfg.expandDepth(s, 0)
+}
+
+func (cfg *Config) expandDepth(s string, depth int) string {
+	if s == "" {
+		return s
+	}
+	replace := func(pattern *regexp.Regexp) {
+		s = pattern.ReplaceAllStringFunc(s, func(match string) string {
+			name := pattern.FindStringSubmatch(match)[1]
+			if v, ok := cfg.lookupVar(name, depth); ok {
+				return v
+			}
+			return match
+		})
+	}
+	replace(bracedVarPattern)
+	replace(percentVarPattern)
+
+	if s == "~" || strings.HasPrefix(s, "~/") || strings.HasPrefix(s, `~\`) {
+		if home, ok := cfg.lookupVar("HOME", depth); ok {
+			s = home + s[1:]
+		}
+	}
+	return s
+}
+
+// unresolvedVars returns the variables in s that have no value
+func (cfg *Config) unresolvedVars(s string) []string {
+	var missing []string
+	for _, pattern := range []*regexp.Regexp{bracedVarPattern, percentVarPattern} {
+		for _, match := range pattern.FindAllStringSubmatch(s, -1) {
+			if _, ok := cfg.lookupVar(match[1], 0); !ok {
+				missing = append(missing, match[0])
+			}
+		}
+	}
+	return missing
+}
+
+// resolveApp returns a copy of app with its process names, path and
+// arguments expanded, ready to be matched or launched
+func (cfg *Config) resolveApp(app AppEntry) AppEntry {
+	app.ProcessName = cfg.expand(app.ProcessName)
+	app.ExecPath = cfg.expand(app.ExecPath)
+	if len(app.Args) > 0 {
+		args := make([]string, len(app.Args))
+		for i, a := range app.Args {
+			args[i] = cfg.expand(a)
+		}
+		app.Args = args
+	}
+	return app
+}
+
+// launchCommand builds the command that restores a resolved app
+func launchCommand(app AppEntry) *exec.Cmd {
+	return exec.Command(app.ExecPath, app.Args...)
+}
+
+// --- Rewriting Paths as Variables ---
+
+// pathVariable is a variable whose value is a directory
+type pathVariable struct {
+	name  string
+	value string
+}
+
+// pathVariables lists the variables absolute paths can be rewritten into.
+// Custom variables come first so they win over environment variables with
+// the same value.
+func (cfg *Config) pathVariables() []pathVariable {
+	var vars []pathVariable
+	custom := make([]string, 0, len(cfg.Variables))
+	for name := range cfg.Variables {
+		custom = append(custom, name)
+	}
+	sort.Strings(custom)
+
+	names := append(custom, "LOCALAPPDATA", "APPDATA", "ProgramFiles(x86)", "ProgramFiles", "ProgramData", "USERPROFILE", "HOME")
+	for _, name := range names {
+		value, ok := cfg.lookupVar(name, 0)
+		if !ok || !filepath.IsAbs(value) {
+			continue
+		}
+		vars = append(vars, pathVariable{name: name, value: filepath.Clean(value)})
+	}
+	return vars
+}
+
+// contractPath rewrites the longest directory prefix of an absolute path
+// into a variable, e.g. C:\Users\alice\AppData\Local\Discord to
+// ${LOCALAPPDATA}\Discord
+func (cfg *Config) contractPath(path string) string {
+	if !filepath.IsAbs(path) {
+		return path
+	}
+
+	best := pathVariable{}
+	for _, v := range cfg.pathVariables() {
+		if len(v.value) > len(best.value) && hasPathPrefix(path, v.value) {
+			best = v
+		}
+	}
+	if best.name == "" {
+		return path
+	}
+	return "${" + best.name + "}" + path[len(best.value):]
+}
+
+// hasPathPrefix reports whether dir contains path, ignoring case on Windows
+func hasPathPrefix(path, dir string) bool {
+	if len(path) < len(dir) {
+		return false
+	}
+	prefix := path[:len(dir)]
+	if runtime.GOOS == "windows" {
+		if !strings.EqualFold(prefix, dir) {
+			return false
+		}
+	} else if prefix != dir {
+		return false
+	}
+	return len(path) == len(dir) || os.IsPathSeparator(path[len(dir)])
+}
+
+// contractApp rewrites the paths of an app entry into variables
+func (cfg *Config) contractApp(app AppEntry) AppEntry {
+	app.ExecPath = cfg.contractPath(app.ExecPath)
+	if len(app.Args) > 0 {
+		args := make([]string, len(app.Args))
+		for i, a := range app.Args {
+			args[i] = cfg.contractPath(a)
+		}
+		app.Args = args
+	}
+	return app
+}
+
+// --- Argument Lists ---
+
+// splitArgs splits a command line typed in the app editor, honouring
+// double quotes
+func splitArgs(s string) []string {
+	var args []string
+	var current strings.Builder
+	inQuotes, started := false, false
+	for _, r := range s {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			started = true
+		case (r == ' ' || r == '\t') && !inQuotes:
+			if started {
+				args = append(args, current.String())
+				current.Reset()
+				started = false
+			}
+		default:
+			current.WriteRune(r)
+			started = true
+		}
+	}
+	if started {
+		args = append(args, current.String())
+	}
+	return args
+}
+
+// joinArgs is the inverse of splitArgs
+func joinArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		if a == "" || strings.ContainsAny(a, " \t") {
+			a = `"` + a + `"`
+		}
+		quoted[i] = a
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Live Reload ---
+//
+// config.yaml and theme.yaml are polled once a second. A change made outside
+// SceneShift is loaded into the running model when no edit is in progress,
+// keeping app selection and suspended PIDs. If the app saves while the files
+// have changed underneath it, nothing is written until the user picks which
+// version to keep.
+
+const configWatchInterval = time.Second
+
+type configWatchMsg time.Time
+
+func watchConfigCmd() tea.Cmd {
+	return tea.Tick(configWatchInterval, func(t time.Time) tea.Msg {
+		return configWatchMsg(t)
+	})
+}
+
+// fileSum returns the SHA256 of a file, or "" if it cannot be read
+func fileSum(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return sha256Hex(data)
+}
+
+// configSum hashes config.yaml together with any files it includes
+func configSum(cfg Config) string {
+	var sums []string
+	for _, file := range cfg.sourceFiles() {
+		sums = append(sums, fileSum(file))
+	}
+	return strings.Join(sums, ",")
+}
+
+// syncFileSums records the files on disk as the version the model holds
+func (m *model) syncFileSums() {
+	m.configSum = configSum(m.config)
+	m.themeSum = fileSum(themeFile())
+}
+
+// changedOnDisk reports whether config.yaml, its includes or theme.yaml
+// differ from the version last loaded or saved
+func (m model) changedOnDisk() bool {
+	return configSum(m.config) != m.configSum || fileSum(themeFile()) != m.themeSum
+}
+
+// canLiveReload reports whether the current screen has no edit in progress
+// and does not depend on app indices, so the config can be swapped out
+func (m model) canLiveReload() bool {
+	switch m.currentState {
+	case stateMenu, statePresetList, stateHistory, stateDone, stateDiagnostics, stateAppDetail,
+		stateProcessExplorer:
+		return true
+	case stateSafelistManager:
+		return m.safelistInput.Value() == ""
+	}
+	return false
+}
+
+// updateConfigWatch reloads files changed outside SceneShift
+func (m model) updateConfigWatch() (tea.Model, tea.Cmd) {
+	if m.configBroken || m.reloadConflict || !m.changedOnDisk() {
+		return m, watchConfigCmd()
+	}
+	if !m.canLiveReload() {
+		// Picked up once the edit is finished, or raised as a conflict on save
+		return m, watchConfigCmd()
+	}
+
+	sum := configSum(m.config) + fileSum(themeFile())
+	if sum == m.badReloadSum {
+		return m, watchConfigCmd()
+	}
+	if err := m.reloadFromDisk(); err != nil {
+		// Likely saved halfway through an edit; keep running on the current config
+		m.badReloadSum = sum
+		m.statusMessage = glyphs("⚠️ ") + err.Error() + "; keeping current settings"
+		return m, watchConfigCmd()
+	}
+	m.statusMessage = glyphs("🔄 Reloaded changes to ") + filepath.Base(configFile) + " / " + filepath.Base(themeFile())
+	if len(m.diagnostics) > 0 {
+		m.statusMessage += " (problems found, run config validate)"
+	}
+	return m, watchConfigCmd()
+}
+
+// reloadFromDisk replaces the in-memory config with the files on disk,
+// carrying over app selection and suspended PIDs by app name. A config.yaml
+// that has been deleted or moved away is reported rather than recreated
+// with the defaults.
+func (m *model) reloadFromDisk() error {
+	if _, err := os.Stat(configFile); errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%s was deleted or moved", filepath.Base(configFile))
+	}
+	cfg, _, _, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	previous := map[string]AppEntry{}
+	for _, app := range m.config.Apps {
+		previous[strings.ToLower(app.Name)] = app
+	}
+	for i := range cfg.Apps {
+		if prev, ok := previous[strings.ToLower(cfg.Apps[i].Name)]; ok {
+			cfg.Apps[i].Selected = prev.Selected
+			cfg.Apps[i].PIDs = prev.PIDs
+		}
+	}
+
+	m.config = cfg
+	m.keys = newKeyMap(cfg.Hotkeys)
+	m.audit = NewAuditLogger(cfg.Audit)
+	m.diagnostics = validateConfigFiles()
+	m.badReloadSum = ""
+	m.syncFileSums()
+
+	if m.cursor >= len(m.config.Apps) {
+		m.cursor = max(len(m.config.Apps)-1, 0)
+	}
+	if m.presetCursor >= len(m.config.Presets) {
+		m.presetCursor = max(len(m.config.Presets)-1, 0)
+	}
+	if m.safelistCursor >= m.safelistLen() {
+		m.safelistCursor = max(m.safelistLen()-1, 0)
+	}
+	return nil
+}
+
+// saveAndQuit saves before quitting, staying open if a conflict needs
+// resolving first
+func (m model) saveAndQuit() (tea.Model, tea.Cmd) {
+	m.saveConfig()
+	if m.reloadConflict {
+		m.quitAfterConflict = true
+		return m, nil
+	}
+	return m, tea.Quit
+}
+
+// updateReloadConflict resolves a save that would overwrite outside edits
+func (m model) updateReloadConflict(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "m":
+		// Keep the in-app version and overwrite the files
+		m.reloadConflict = false
+		m.syncFileSums()
+		m.saveConfig()
+		m.statusMessage = glyphs("💾 Kept SceneShift's changes; files on disk overwritten")
+	case "f":
+		// Keep the files on disk and drop the unsaved in-app changes
+		if err := m.reloadFromDisk(); err != nil {
+			m.conflictErr = err
+			return m, nil
+		}
+		m.reloadConflict = false
+		m.statusMessage = glyphs("🔄 Kept the changes on disk; in-app changes discarded")
+	case "ctrl+c":
+		return m, tea.Quit
+	default:
+		return m, nil
+	}
+
+	m.conflictErr = nil
+	if m.quitAfterConflict {
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m model) viewReloadConflict() string {
+	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Select)).Bold(true).Underline(true)
+	base := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Text))
+	warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Warn))
+	killStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Kill)).Bold(true)
+
+	var s string
+	s += titleStyle.Render(glyphs("⚠️  CONFIG CHANGED ON DISK")) + "\n\n"
+	s += base.Render(filepath.Base(configFile)+" or "+filepath.Base(themeFile())+" was edited outside SceneShift") + "\n"
+	s += base.Render("while you had unsaved changes here.") + "\n\n"
+	s += warnStyle.Render("Whichever version you don't keep is lost.") + "\n"
+	if m.conflictErr != nil {
+		s += "\n" + killStyle.Render(glyphs("❌ ")+m.conflictErr.Error()) + "\n"
+	}
+	s += "\n" + lipgloss.NewStyle().Faint(true).Render(glyphs("m: Keep mine (overwrite file) • f: Keep file (discard mine)")) + "\n"
+	return s
+}